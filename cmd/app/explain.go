@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	fyi "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	explainoptions "github.com/tfadeyi/errors/cmd/app/options/explain"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/errorclient/local"
+	"github.com/tfadeyi/errors/internal/explain"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/parser"
+	"github.com/tfadeyi/errors/internal/parser/language"
+	"github.com/tfadeyi/errors/internal/parser/options"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func explainCmd(common *commonoptions.Options) *cobra.Command {
+	opts := explainoptions.New(common)
+
+	cmd := &cobra.Command{
+		Use:          "explain <code>",
+		Short:        "Prints the details of the given error code",
+		Long:         ``,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+			logger = logger.WithName("explain")
+
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			cmd.SetContext(logging.ContextWithLogger(cmd.Context(), logger))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			logger := logging.LoggerFromContext(ctx)
+			code := args[0]
+
+			manifests, err := loadManifests(ctx, opts, &logger, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			explanation, ok := explain.Find(manifests, code)
+			if !ok {
+				suggestions := explain.Suggest(manifests, code)
+				if opts.JSON {
+					if err := explain.WriteJSON(cmd.OutOrStdout(), map[string]any{
+						"code":        code,
+						"suggestions": suggestions,
+					}); err != nil {
+						return err
+					}
+				} else if len(suggestions) > 0 {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Did you mean: %s?\n", strings.Join(suggestions, ", "))
+				}
+				// @fyi.error code unknown_error_code
+				// @fyi.error title Unknown Error Code
+				// @fyi.error short The error code passed to the explain command is not defined in the application error manifest.
+				return fyi.Error(errors.Errorf("no error with code %q was found", code), "unknown_error_code")
+			}
+
+			if opts.JSON {
+				return explain.WriteJSON(cmd.OutOrStdout(), explanation)
+			}
+			return explain.Write(cmd.OutOrStdout(), explanation, explain.RenderOptions{
+				Color: opts.UseColor(cmd.OutOrStdout()),
+				Width: opts.Width,
+			})
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
+
+// loadManifests reads the manifest passed to --manifest or parses the included directories, printing the problems found
+// in the annotations to stderr
+func loadManifests(ctx context.Context, opts *explainoptions.Options, logger *logging.Logger, stderr io.Writer) (map[string]*api.Manifest, error) {
+	if opts.Manifest != "" {
+		body, err := os.ReadFile(opts.Manifest)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to read the application error manifest %q", opts.Manifest)
+		}
+		decoded, err := local.DecodeManifests(body)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to decode the application error manifest %q", opts.Manifest)
+		}
+		manifests := make(map[string]*api.Manifest, len(decoded))
+		for _, manifest := range decoded {
+			if _, ok := manifests[manifest.Name]; ok {
+				return nil, errors.Errorf("the application %q is defined twice in the application error manifest %q", manifest.Name, opts.Manifest)
+			}
+			manifests[manifest.Name] = manifest
+		}
+		return manifests, nil
+	}
+
+	parserOptions := []options.Option{
+		options.Include(opts.IncludedDirs...),
+		options.Logger(logger),
+	}
+
+	switch opts.Language {
+	case language.Go:
+		parserOptions = append(parserOptions, options.Go())
+	default:
+		// do nothing
+	}
+
+	p := parser.New(parserOptions...)
+	apps, err := p.Parse(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse the application(s) error manifests")
+	}
+	if err := p.Diagnostics().Write(stderr, diagnostic.Text); err != nil {
+		logger.Warn(errors.Annotate(err, "failed to print the problems found in the annotations"))
+	}
+
+	manifests := make(map[string]*api.Manifest, len(apps))
+	for name, app := range apps {
+		manifest, ok := app.(*api.Manifest)
+		if !ok {
+			return nil, errors.New("found invalid application errors manifest")
+		}
+		manifests[name] = manifest
+	}
+	return manifests, nil
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	fyi "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	initoptions "github.com/tfadeyi/errors/cmd/app/options/initialise"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/scaffold"
+)
+
+func initCmd(common *commonoptions.Options) *cobra.Command {
+	opts := initoptions.New(common)
+
+	cmd := &cobra.Command{
+		Use:          "init",
+		Short:        "Sets up the error manifest, annotations and code generation in the module's main package",
+		Long:         ``,
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+			logger = logger.WithName("init")
+
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			cmd.SetContext(logging.ContextWithLogger(cmd.Context(), logger))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+
+			s := scaffold.New(&scaffold.Options{
+				Logger:       &logger,
+				Dir:          opts.Dir,
+				MainDir:      opts.MainDir,
+				Name:         opts.Name,
+				BaseURL:      opts.BaseURL,
+				Version:      opts.Version,
+				Description:  opts.Description,
+				Manifest:     opts.Manifest,
+				TemplatesDir: opts.TemplatesDir,
+				Watermark: `# Code generated by errctl: https://github.com/tfadeyi/errors.
+# DO NOT EDIT.`,
+				Force: opts.Force,
+			})
+
+			actions, err := s.Init(cmd.Context())
+			if err != nil {
+				// @fyi.error code init_project_error
+				// @fyi.error title Error Initialising The Project
+				// @fyi.error short The tool has failed to set up error.fyi in the go module.
+				// @fyi.error long The tool has failed to set up error.fyi in the go module. Check that the command runs inside a go module with a main package, or point to it with --main.
+				return fyi.Error(errors.Annotate(err, "failed to initialise the project"), "init_project_error")
+			}
+
+			for _, action := range actions {
+				if action.Reason != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "%-8s %s (%s)\n", action.Status, action.Path, action.Reason)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-8s %s\n", action.Status, action.Path)
+			}
+			return nil
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	migrateoptions "github.com/tfadeyi/errors/cmd/app/options/migrate"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/migrate"
+)
+
+func migrateCmd(common *commonoptions.Options) *cobra.Command {
+	opts := migrateoptions.New(common)
+
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Rewrites the legacy annotations (i.e: @aloe) in the source code into the current @fyi grammar",
+		Long:         ``,
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+			logger = logger.WithName("migrate")
+
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			cmd.SetContext(logging.ContextWithLogger(cmd.Context(), logger))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+
+			logger.Info("Rewriting legacy annotations ⚙️",
+				"directories", opts.IncludedDirs,
+				"prefixes", opts.Prefixes,
+			)
+
+			m := migrate.New(&migrate.Options{
+				Logger:   &logger,
+				Prefixes: opts.Prefixes,
+				DryRun:   opts.DryRun,
+			})
+			results, err := m.Migrate(cmd.Context(), opts.IncludedDirs...)
+			if err != nil {
+				return errors.Annotate(err, "failed to migrate the legacy annotations")
+			}
+
+			for _, result := range results {
+				if opts.DryRun {
+					fmt.Fprint(cmd.OutOrStdout(), result.Diff)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "migrated %d annotation(s) in %s\n", result.Annotations, result.File)
+			}
+
+			logger.Info("Legacy annotations were successfully rewritten ✅", "files", len(results))
+			return nil
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
@@ -32,10 +32,10 @@ func (o *Options) Prepare(cmd *cobra.Command) *Options {
 func (o *Options) Complete() error {
 	var err error
 	if !logging.IsValidLevel(o.LogLevel) {
-		// @aloe code invalid_log_level
-		// @aloe title Invalid Log-Level Argument
-		// @aloe short The log level passed to the --log-level flag is not supported.
-		// @aloe long The log level passed to the --log-level flag is not currently supported by the tool.
+		// @fyi.error code invalid_log_level
+		// @fyi.error title Invalid Log-Level Argument
+		// @fyi.error short The log level passed to the --log-level flag is not supported.
+		// @fyi.error long The log level passed to the --log-level flag is not currently supported by the tool.
 		// The following are supported: none, debug, info(default), warn.
 		err = multierr.Append(err, errors.Errorf("invalid log-level %q was passed to --log-level flag", o.LogLevel))
 	}
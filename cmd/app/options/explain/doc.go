@@ -0,0 +1,2 @@
+// Package explain contains the different options present under the error explain command.
+package explain
@@ -0,0 +1,142 @@
+package explain
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	errhandler "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	"github.com/tfadeyi/errors/internal/parser/language"
+)
+
+type (
+	// Options is the list of options/flag available to the application,
+	// plus the clients needed by the application to function.
+	Options struct {
+		Manifest     string
+		IncludedDirs []string
+		Language     string
+		JSON         bool
+		Color        string
+		Width        int
+		*commonoptions.Options
+	}
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	defaultWidth = 80
+)
+
+// New creates a new instance of the application's options
+func New(common *commonoptions.Options) *Options {
+	opts := new(Options)
+	opts.Options = common
+	return opts
+}
+
+// Prepare assigns the applications flag/options to the cobra cli
+func (o *Options) Prepare(cmd *cobra.Command) *Options {
+	o.addAppFlags(cmd.Flags())
+	return o
+}
+
+// Complete initialises the components needed for the application to function given the options
+func (o *Options) Complete() error {
+	o.Color = strings.ToLower(strings.TrimSpace(o.Color))
+	switch o.Color {
+	case ColorAuto, ColorAlways, ColorNever:
+	default:
+		// @fyi.error code invalid_color_mode
+		// @fyi.error title Invalid Color Mode
+		// @fyi.error short The value passed to --color is not valid, valid: auto, always, never
+		return errhandler.Error(errors.Errorf("the color mode given %q is not valid", o.Color), "invalid_color_mode")
+	}
+
+	if o.Width <= 0 {
+		o.Width = defaultWidth
+		if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+			o.Width = columns
+		}
+	}
+	return nil
+}
+
+// UseColor returns true if the output should be colored, given the color mode and the output file
+func (o *Options) UseColor(out any) bool {
+	switch o.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func getWorkingDirOrDie() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func (o *Options) addAppFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(
+		&o.Manifest,
+		"manifest",
+		"m",
+		"",
+		"Application error manifest file to look the code up in, the included directories are parsed if not set",
+	)
+	fs.StringSliceVarP(
+		&o.IncludedDirs,
+		"include",
+		"d",
+		[]string{getWorkingDirOrDie()},
+		"Comma separated list of directories to be parses by the tool",
+	)
+	fs.StringVarP(
+		&o.Language,
+		"language",
+		"l",
+		language.Go,
+		"Target source code language",
+	)
+	fs.BoolVar(
+		&o.JSON,
+		"json",
+		false,
+		"Print the error definition as JSON",
+	)
+	fs.StringVar(
+		&o.Color,
+		"color",
+		ColorAuto,
+		"Color the output (auto,always,never)",
+	)
+	fs.IntVar(
+		&o.Width,
+		"width",
+		0,
+		"Column at which the output is wrapped, defaults to $COLUMNS or 80",
+	)
+}
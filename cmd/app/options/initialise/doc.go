@@ -0,0 +1,2 @@
+// Package initialise contains the different options present under the project init command.
+package initialise
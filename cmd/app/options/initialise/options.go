@@ -0,0 +1,112 @@
+package initialise
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	"github.com/tfadeyi/errors/internal/scaffold"
+)
+
+type (
+	// Options is the list of options/flag available to the application,
+	// plus the clients needed by the application to function.
+	Options struct {
+		Dir          string
+		MainDir      string
+		Name         string
+		BaseURL      string
+		Version      string
+		Description  string
+		Manifest     string
+		TemplatesDir string
+		Force        bool
+		*commonoptions.Options
+	}
+)
+
+// New creates a new instance of the application's options
+func New(common *commonoptions.Options) *Options {
+	opts := new(Options)
+	opts.Options = common
+	return opts
+}
+
+// Prepare assigns the applications flag/options to the cobra cli
+func (o *Options) Prepare(cmd *cobra.Command) *Options {
+	o.addAppFlags(cmd.Flags())
+	return o
+}
+
+// Complete initialises the components needed for the application to function given the options
+func (o *Options) Complete() error {
+	return nil
+}
+
+func getWorkingDirOrDie() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func (o *Options) addAppFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(
+		&o.Dir,
+		"dir",
+		"d",
+		getWorkingDirOrDie(),
+		"Directory inside the target go module",
+	)
+	fs.StringVar(
+		&o.MainDir,
+		"main",
+		"",
+		"Directory of the application's main package, detected if not set",
+	)
+	fs.StringVar(
+		&o.Name,
+		"name",
+		"",
+		"Application name, defaults to the last element of the module path",
+	)
+	fs.StringVar(
+		&o.BaseURL,
+		"base-url",
+		"",
+		"Base URL of the application error documentation, defaults to the GitHub pages URL of the module",
+	)
+	fs.StringVar(
+		&o.Version,
+		"version",
+		scaffold.DefaultVersion,
+		"Application version",
+	)
+	fs.StringVar(
+		&o.Description,
+		"description",
+		"",
+		"Application description",
+	)
+	fs.StringVarP(
+		&o.Manifest,
+		"output",
+		"o",
+		scaffold.DefaultManifest,
+		"Filename of the application error manifest, created next to the main package",
+	)
+	fs.StringVar(
+		&o.TemplatesDir,
+		"templates",
+		"",
+		"Directory where the default markdown templates are copied to for customisation, skipped if not set",
+	)
+	fs.BoolVar(
+		&o.Force,
+		"force",
+		false,
+		"Overwrite the existing manifest and template files",
+	)
+}
@@ -0,0 +1,2 @@
+// Package migrate contains the different options present under the annotations migrate command.
+package migrate
@@ -0,0 +1,2 @@
+// Package serve contains the different options present under the documentation serve command.
+package serve
@@ -0,0 +1,114 @@
+package serve
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	"github.com/tfadeyi/errors/internal/parser/language"
+	"github.com/tfadeyi/errors/internal/server"
+)
+
+type (
+	// Options is the list of options/flag available to the application,
+	// plus the clients needed by the application to function.
+	Options struct {
+		Address       string
+		IncludedDirs  []string
+		Exclude       []string
+		IncludeTests  bool
+		BuildTags     []string
+		Language      string
+		ErrorTemplate string
+		InfoTemplate  string
+		NoReload      bool
+		*commonoptions.Options
+	}
+)
+
+// New creates a new instance of the application's options
+func New(common *commonoptions.Options) *Options {
+	opts := new(Options)
+	opts.Options = common
+	return opts
+}
+
+// Prepare assigns the applications flag/options to the cobra cli
+func (o *Options) Prepare(cmd *cobra.Command) *Options {
+	o.addAppFlags(cmd.Flags())
+	return o
+}
+
+// Complete initialises the components needed for the application to function given the options
+func (o *Options) Complete() error {
+	return nil
+}
+
+func getWorkingDirOrDie() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func (o *Options) addAppFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(
+		&o.Address,
+		"address",
+		"a",
+		server.DefaultAddress,
+		"Address the documentation server listens on",
+	)
+	fs.StringSliceVarP(
+		&o.IncludedDirs,
+		"include",
+		"d",
+		[]string{getWorkingDirOrDie()},
+		"Comma separated list of directories to be parses by the tool",
+	)
+	fs.StringSliceVar(
+		&o.Exclude,
+		"exclude",
+		[]string{},
+		"Comma separated list of glob patterns of the files and directories to skip, i.e: mocks,**/zz_generated_*.go. Vendor, testdata and hidden directories are always skipped",
+	)
+	fs.BoolVar(
+		&o.IncludeTests,
+		"tests",
+		false,
+		"Also parse the _test.go files",
+	)
+	fs.StringSliceVar(
+		&o.BuildTags,
+		"tags",
+		[]string{},
+		"Comma separated list of build tags considered satisfied, only the files built for the tags, $GOOS and $GOARCH are parsed",
+	)
+	fs.StringVarP(
+		&o.Language,
+		"language",
+		"l",
+		language.Go,
+		"Target source code language",
+	)
+	fs.StringVar(
+		&o.ErrorTemplate,
+		"error-template",
+		"",
+		"Custom application error go-template filepath (markdown)",
+	)
+	fs.StringVar(
+		&o.InfoTemplate,
+		"info-template",
+		"",
+		"Custom application information go-template filepath (markdown)",
+	)
+	fs.BoolVar(
+		&o.NoReload,
+		"no-reload",
+		false,
+		"Disable re-parsing the source code and reloading the browser when the sources or templates change",
+	)
+}
@@ -9,7 +9,12 @@ import (
 	"github.com/spf13/pflag"
 	errhandler "github.com/tfadeyi/errors"
 	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	"github.com/tfadeyi/errors/internal/config"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/fragment"
 	"github.com/tfadeyi/errors/internal/parser/generate"
+	"github.com/tfadeyi/errors/internal/parser/generate/helpers"
+	"github.com/tfadeyi/errors/internal/parser/generate/yaml"
 	"github.com/tfadeyi/errors/internal/parser/language"
 )
 
@@ -24,10 +29,51 @@ type (
 		Language               string
 		ErrorTemplate          string
 		InfoTemplate           string
+		TemplateDir            string
+		SourceURL              string
+		LegacyPrefixes         []string
+		Watch                  bool
+		Check                  bool
+		Exclude                []string
+		IncludeTests           bool
+		BuildTags              []string
+		Watermark              string
+		Jobs                   int
+		NoCache                bool
+		// Fragments are the glob patterns, relative to the included directories, of the fragment files completing
+		// the errors of the annotations
+		Fragments []string
+		// Merge merges the generated YAML manifest into the existing output file, keeping the hand-edited fields
+		Merge bool
+		// Orphans is how the merge handles the errors missing from the source code, mark or drop
+		Orphans string
+		// Compact prints each JSON manifest on a single line
+		Compact bool
+		// Strict fails the generation if any problem is found in the annotations
+		Strict bool
+		// DiagnosticsFormat is the format the problems found in the annotations are printed in, text or json
+		DiagnosticsFormat string
+		// ConfigFile is the path to the project configuration file, it is discovered if not set
+		ConfigFile string
+		// Target is the name of the configuration file target to generate, all targets are generated if not set
+		Target string
+		// Name is the name of the configuration file target the options were resolved from
+		Name string
+		// Targets are the options of each generation target, resolved by Complete from the configuration file targets
+		// and the command flags. The command flags take precedence over the configuration file values.
+		Targets []*Options
 		*commonoptions.Options
+
+		flags *pflag.FlagSet
 	}
 )
 
+const (
+	// DefaultWatermark is the header of the generated files
+	DefaultWatermark = `# Code generated by errctl: https://github.com/tfadeyi/errors.
+# DO NOT EDIT.`
+)
+
 // New creates a new instance of the application's options
 func New(common *commonoptions.Options) *Options {
 	opts := new(Options)
@@ -43,17 +89,195 @@ func (o *Options) Prepare(cmd *cobra.Command) *Options {
 
 // Complete initialises the components needed for the application to function given the options
 func (o *Options) Complete() error {
+	targets, err := o.resolveTargets()
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := target.validate(); err != nil {
+			return err
+		}
+	}
+	o.Targets = targets
+	return nil
+}
+
+// resolveTargets returns the options of the configuration file targets, overridden by the flags set by the user.
+// The options are returned as they are if no configuration file is found.
+func (o *Options) resolveTargets() ([]*Options, error) {
+	path := o.ConfigFile
+	if path == "" {
+		found, err := config.Find(getWorkingDirOrDie())
+		if errors.Is(err, config.ErrNotFound) && o.Target == "" {
+			return []*Options{o}, nil
+		}
+		if err != nil {
+			// @fyi.error code config_not_found
+			// @fyi.error title Configuration File Not Found
+			// @fyi.error short A target was passed to --target but no .errctl.yaml configuration file was found up to the module root.
+			return nil, errhandler.Error(err, "config_not_found")
+		}
+		path = found
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		// @fyi.error code invalid_config
+		// @fyi.error title Invalid Configuration File
+		// @fyi.error short The .errctl.yaml configuration file could not be read or contains unknown fields.
+		return nil, errhandler.Error(err, "invalid_config")
+	}
+	selected, err := cfg.Select(o.Target)
+	if err != nil {
+		// @fyi.error code unknown_target
+		// @fyi.error title Unknown Generation Target
+		// @fyi.error short The target passed to --target is not defined in the .errctl.yaml configuration file.
+		return nil, errhandler.Error(err, "unknown_target")
+	}
+
+	var targets []*Options
+	for _, target := range selected {
+		opts := *o
+		opts.Name = target.Name
+		opts.Targets = nil
+		if target.Format != "" && !o.changed("format") {
+			opts.Format = target.Format
+		}
+		if target.Output != "" && !o.changed("output") {
+			opts.OutputFileAndDirectory = target.Output
+		}
+		if target.Language != "" && !o.changed("language") {
+			opts.Language = target.Language
+		}
+		if len(target.Include) > 0 && !o.changed("include") {
+			opts.IncludedDirs = target.Include
+		}
+		if len(target.Exclude) > 0 && !o.changed("exclude") {
+			opts.Exclude = target.Exclude
+		}
+		if target.Tests != nil && !o.changed("tests") {
+			opts.IncludeTests = *target.Tests
+		}
+		if len(target.Tags) > 0 && !o.changed("tags") {
+			opts.BuildTags = target.Tags
+		}
+		if target.InfoTemplate != "" && !o.changed("info-template") {
+			opts.InfoTemplate = target.InfoTemplate
+		}
+		if target.ErrorTemplate != "" && !o.changed("error-template") {
+			opts.ErrorTemplate = target.ErrorTemplate
+		}
+		if target.TemplateDir != "" && !o.changed("template-dir") {
+			opts.TemplateDir = target.TemplateDir
+		}
+		if target.SourceURL != "" && !o.changed("source-url") {
+			opts.SourceURL = target.SourceURL
+		}
+		if target.Watermark != nil {
+			opts.Watermark = *target.Watermark
+		}
+		if target.Fragments != nil && !o.changed("fragments") {
+			opts.Fragments = target.Fragments
+		}
+		if target.Merge != nil && !o.changed("merge") {
+			opts.Merge = *target.Merge
+		}
+		if target.Orphans != "" && !o.changed("orphans") {
+			opts.Orphans = target.Orphans
+		}
+		if target.Compact != nil && !o.changed("compact") {
+			opts.Compact = *target.Compact
+		}
+		targets = append(targets, &opts)
+	}
+	return targets, nil
+}
+
+// changed checks if the flag was set by the user
+func (o *Options) changed(name string) bool {
+	return o.flags != nil && o.flags.Changed(name)
+}
+
+// validate checks the options of a generation target
+func (o *Options) validate() error {
 	selectedFormat := strings.ToLower(strings.TrimSpace(o.Format))
 	if !generate.IsSupportedOutputFormat(selectedFormat) {
 		// @fyi.error code invalid_output_format
 		// @fyi.error title invalid_output_format
-		// @fyi.error short the output format passed to --format was invalid, valid: yaml, markdown
+		// @fyi.error short the output format passed to --format was invalid, valid: yaml, json, markdown, html
 		return errhandler.Error(errors.Errorf("the output format given %q is not valid", o.Format), "invalid_output_format")
 	}
+	o.Format = selectedFormat
+
+	diagnosticsFormat := strings.ToLower(strings.TrimSpace(o.DiagnosticsFormat))
+	if !diagnostic.IsSupportedFormat(diagnosticsFormat) {
+		// @fyi.error code invalid_diagnostics_format
+		// @fyi.error title Invalid Diagnostics Format
+		// @fyi.error short The format passed to --diagnostics-format was invalid, valid: text, json
+		return errhandler.Error(errors.Errorf("the diagnostics format given %q is not valid", o.DiagnosticsFormat), "invalid_diagnostics_format")
+	}
+	o.DiagnosticsFormat = diagnosticsFormat
+
+	if o.Watch && o.Source == "-" {
+		// @fyi.error code invalid_watch_source
+		// @fyi.error title Invalid Watch Source
+		// @fyi.error short The standard input cannot be watched for changes, remove --watch or pass a file to --file.
+		return errhandler.Error(errors.New("--watch can't be used when reading the source code from the standard input"), "invalid_watch_source")
+	}
+
+	if o.Check && o.Watch {
+		// @fyi.error code invalid_check_watch
+		// @fyi.error title Invalid Check Mode
+		// @fyi.error short --check cannot be used together with --watch.
+		return errhandler.Error(errors.New("--check can't be used together with --watch"), "invalid_check_watch")
+	}
+	if o.Check && o.OutputFileAndDirectory == "" {
+		// @fyi.error code invalid_check_output
+		// @fyi.error title Missing Check Output
+		// @fyi.error short --check compares the generated content with the files on disk, an output file or directory has to be passed to --output.
+		return errhandler.Error(errors.New("--check requires an output file or directory"), "invalid_check_output")
+	}
+
+	orphans := strings.ToLower(strings.TrimSpace(o.Orphans))
+	if !yaml.IsSupportedOrphans(orphans) {
+		// @fyi.error code invalid_orphans
+		// @fyi.error title Invalid Orphans Mode
+		// @fyi.error short The mode passed to --orphans was invalid, valid: mark, drop
+		return errhandler.Error(errors.Errorf("the orphans mode given %q is not valid", o.Orphans), "invalid_orphans")
+	}
+	o.Orphans = orphans
+	if o.Merge && (o.Format != generate.Yaml || o.OutputFileAndDirectory == "") {
+		// @fyi.error code invalid_merge
+		// @fyi.error title Invalid Merge Mode
+		// @fyi.error short --merge updates an existing YAML manifest, it requires the yaml format and an output file passed to --output.
+		return errhandler.Error(errors.New("--merge requires the yaml format and an output file"), "invalid_merge")
+	}
+
+	if helpers.IsOutputPattern(o.OutputFileAndDirectory) && o.Format != generate.Yaml && o.Format != generate.JSON {
+		// @fyi.error code invalid_output_pattern
+		// @fyi.error title Invalid Output Pattern
+		// @fyi.error short The {name} placeholder of --output renders each application to its own file, it's only supported by the yaml and json formats.
+		return errhandler.Error(errors.Errorf("the output %q contains %s, which is only supported by the yaml and json formats", o.OutputFileAndDirectory, helpers.NamePlaceholder), "invalid_output_pattern")
+	}
+
+	if o.Format == generate.HTML && o.OutputFileAndDirectory == "" {
+		// @fyi.error code invalid_html_output
+		// @fyi.error title Missing HTML Output Directory
+		// @fyi.error short The html format generates a static site, an output directory has to be passed to --output.
+		return errhandler.Error(errors.New("the html format requires an output directory"), "invalid_html_output")
+	}
+	if o.TemplateDir != "" {
+		if info, err := os.Stat(o.TemplateDir); err != nil || !info.IsDir() {
+			// @fyi.error code invalid_template_dir
+			// @fyi.error title Invalid Template Directory
+			// @fyi.error short The directory passed to --template-dir doesn't exist or isn't a directory.
+			return errhandler.Error(errors.Errorf("the template directory %q is not a directory", o.TemplateDir), "invalid_template_dir")
+		}
+	}
 
 	// Check if output is a directory and error if the format chosen is YAML
 	if file, err := os.Stat(o.OutputFileAndDirectory); !errors.Is(err, os.ErrNotExist) {
-		if file.IsDir() && o.Format == generate.Yaml {
+		if file.IsDir() && (o.Format == generate.Yaml || o.Format == generate.JSON) {
 			// Here we add more specific info about the error they may encounter in this configuration
 
 			// @fyi.error code invalid_yaml_output_file
@@ -75,6 +299,8 @@ func getWorkingDirOrDie() string {
 }
 
 func (o *Options) addAppFlags(fs *pflag.FlagSet) {
+	o.flags = fs
+	o.Watermark = DefaultWatermark
 	fs.StringSliceVarP(
 		&o.IncludedDirs,
 		"include",
@@ -86,14 +312,56 @@ func (o *Options) addAppFlags(fs *pflag.FlagSet) {
 		&o.Format,
 		"format",
 		generate.Yaml,
-		"Output format (yaml,markdown)",
+		"Output format (yaml,json,markdown,html)",
+	)
+	fs.StringSliceVar(
+		&o.Exclude,
+		"exclude",
+		[]string{},
+		"Comma separated list of glob patterns of the files and directories to skip, i.e: mocks,**/zz_generated_*.go. Vendor, testdata and hidden directories are always skipped",
+	)
+	fs.BoolVar(
+		&o.IncludeTests,
+		"tests",
+		false,
+		"Also parse the _test.go files",
+	)
+	fs.StringSliceVar(
+		&o.Fragments,
+		"fragments",
+		fragment.DefaultPatterns,
+		"Comma separated list of glob patterns, relative to the included directories, of the YAML or JSON fragment files completing the errors of the annotations, pass an empty list to skip them",
+	)
+	fs.BoolVar(
+		&o.Merge,
+		"merge",
+		false,
+		"Merge the generated YAML manifest into the existing output file, keeping the fields, comments and key order edited by hand",
+	)
+	fs.StringVar(
+		&o.Orphans,
+		"orphans",
+		yaml.OrphansMark,
+		"How --merge handles the errors and applications of the existing manifest missing from the source code (mark,drop)",
+	)
+	fs.BoolVar(
+		&o.Compact,
+		"compact",
+		false,
+		"Print each JSON manifest on a single line rather than pretty printing it (json)",
+	)
+	fs.StringSliceVar(
+		&o.BuildTags,
+		"tags",
+		[]string{},
+		"Comma separated list of build tags considered satisfied, only the files built for the tags, $GOOS and $GOARCH are parsed",
 	)
 	fs.StringVarP(
 		&o.OutputFileAndDirectory,
 		"output",
 		"o",
 		"",
-		"Target output file or directory to store the generated output",
+		"Target output file or directory to store the generated output, {name} is replaced by the application name to write each YAML or JSON manifest to its own file, i.e: errors-{name}.yaml",
 	)
 	fs.StringVarP(
 		&o.Source,
@@ -121,4 +389,72 @@ func (o *Options) addAppFlags(fs *pflag.FlagSet) {
 		"",
 		"Custom application information go-template filepath (markdown)",
 	)
+	fs.StringVar(
+		&o.TemplateDir,
+		"template-dir",
+		"",
+		"Directory of the templates and assets overriding the files of the default theme with the same name (html)",
+	)
+	fs.StringVar(
+		&o.SourceURL,
+		"source-url",
+		"",
+		"Repository URL template the markdown and html docs link each error to its source with, {path}, {line} and {column} are replaced by the error location, i.e: https://github.com/org/repo/blob/main/{path}#L{line}",
+	)
+	fs.StringSliceVar(
+		&o.LegacyPrefixes,
+		"legacy-prefix",
+		[]string{},
+		"Comma separated list of legacy annotation prefixes (i.e: @aloe) to accept alongside @fyi, see errctl migrate",
+	)
+	fs.BoolVar(
+		&o.Check,
+		"check",
+		false,
+		"Compare the generated content with the files on disk without writing them, exit with an error if they are out of date",
+	)
+	fs.StringVar(
+		&o.ConfigFile,
+		"config",
+		"",
+		"Path to the project configuration file, defaults to the closest "+config.Filename+" up to the module root",
+	)
+	fs.StringVar(
+		&o.Target,
+		"target",
+		"",
+		"Name of the configuration file target to generate, all the targets are generated if not set",
+	)
+	fs.BoolVarP(
+		&o.Watch,
+		"watch",
+		"w",
+		false,
+		"Keep running and regenerate the output whenever the source code changes",
+	)
+	fs.IntVarP(
+		&o.Jobs,
+		"jobs",
+		"j",
+		0,
+		"Maximum number of source files parsed concurrently, defaults to the number of CPUs",
+	)
+	fs.BoolVar(
+		&o.NoCache,
+		"no-cache",
+		false,
+		"Parse all the source files, ignoring the annotations cached by the previous runs for the unchanged files",
+	)
+	fs.BoolVar(
+		&o.Strict,
+		"strict",
+		false,
+		"Exit with an error, without generating the output, if any problem is found in the annotations",
+	)
+	fs.StringVar(
+		&o.DiagnosticsFormat,
+		"diagnostics-format",
+		diagnostic.Text,
+		"Format the problems found in the annotations are printed in to the standard error (text,json)",
+	)
 }
@@ -0,0 +1,2 @@
+// Package stats contains the different options present under the error stats command.
+package stats
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	errhandler "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	"github.com/tfadeyi/errors/internal/parser/language"
+	"github.com/tfadeyi/errors/internal/stats"
+)
+
+type (
+	// Options is the list of options/flag available to the application,
+	// plus the clients needed by the application to function.
+	Options struct {
+		IncludedDirs   []string
+		Exclude        []string
+		IncludeTests   bool
+		BuildTags      []string
+		Language       string
+		Format         string
+		Output         string
+		LegacyPrefixes []string
+		Jobs           int
+		*commonoptions.Options
+	}
+)
+
+// New creates a new instance of the application's options
+func New(common *commonoptions.Options) *Options {
+	opts := new(Options)
+	opts.Options = common
+	return opts
+}
+
+// Prepare assigns the applications flag/options to the cobra cli
+func (o *Options) Prepare(cmd *cobra.Command) *Options {
+	o.addAppFlags(cmd.Flags())
+	return o
+}
+
+// Complete initialises the components needed for the application to function given the options
+func (o *Options) Complete() error {
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	if !stats.IsSupportedFormat(o.Format) {
+		// @fyi.error code invalid_stats_format
+		// @fyi.error title Invalid Statistics Format
+		// @fyi.error short The value passed to --format is not a valid statistics report format, valid: table, json, badge
+		return errhandler.Error(errors.Errorf("the report format given %q is not supported", o.Format), "invalid_stats_format")
+	}
+	return nil
+}
+
+func getWorkingDirOrDie() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func (o *Options) addAppFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVarP(
+		&o.IncludedDirs,
+		"include",
+		"d",
+		[]string{getWorkingDirOrDie()},
+		"Comma separated list of directories to be parses by the tool",
+	)
+	fs.StringSliceVar(
+		&o.Exclude,
+		"exclude",
+		[]string{},
+		"Comma separated list of glob patterns of the files and directories to skip, i.e: mocks,**/zz_generated_*.go. Vendor, testdata and hidden directories are always skipped",
+	)
+	fs.BoolVar(
+		&o.IncludeTests,
+		"tests",
+		false,
+		"Also parse the _test.go files",
+	)
+	fs.StringSliceVar(
+		&o.BuildTags,
+		"tags",
+		[]string{},
+		"Comma separated list of build tags considered satisfied, only the files built for the tags, $GOOS and $GOARCH are parsed",
+	)
+	fs.StringVarP(
+		&o.Language,
+		"language",
+		"l",
+		language.Go,
+		"Target source code language",
+	)
+	fs.StringVarP(
+		&o.Format,
+		"format",
+		"f",
+		stats.Table,
+		"Format of the report (table,json,badge)",
+	)
+	fs.StringVarP(
+		&o.Output,
+		"output",
+		"o",
+		"",
+		"File the report is written to, i.e: coverage.svg, defaults to the standard output",
+	)
+	fs.StringSliceVar(
+		&o.LegacyPrefixes,
+		"legacy-prefix",
+		[]string{},
+		"Legacy annotation prefixes, i.e: @aloe, to parse alongside @fyi",
+	)
+	fs.IntVarP(
+		&o.Jobs,
+		"jobs",
+		"j",
+		0,
+		"Maximum number of source files parsed concurrently, defaults to the number of CPUs",
+	)
+}
@@ -70,5 +70,10 @@ func init() {
 	rootCmd = cmd(opts)
 	rootCmd.AddCommand(specGenerateCmd(opts))
 	rootCmd.AddCommand(specValidateCmd(opts))
+	rootCmd.AddCommand(explainCmd(opts))
+	rootCmd.AddCommand(initCmd(opts))
+	rootCmd.AddCommand(migrateCmd(opts))
+	rootCmd.AddCommand(serveCmd(opts))
+	rootCmd.AddCommand(statsCmd(opts))
 	rootCmd.AddCommand(versionCmd(opts))
 }
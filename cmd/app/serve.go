@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	fyi "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	serveoptions "github.com/tfadeyi/errors/cmd/app/options/serve"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/fragment"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/parser"
+	"github.com/tfadeyi/errors/internal/parser/generate/markdown"
+	"github.com/tfadeyi/errors/internal/parser/language"
+	"github.com/tfadeyi/errors/internal/parser/options"
+	"github.com/tfadeyi/errors/internal/server"
+	"github.com/tfadeyi/errors/internal/watcher"
+)
+
+func serveCmd(common *commonoptions.Options) *cobra.Command {
+	opts := serveoptions.New(common)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serves a live preview of the application(s) markdown error documentation",
+		Long:  ``,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+			logger = logger.WithName("serve")
+
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			cmd.SetContext(logging.ContextWithLogger(cmd.Context(), logger))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			logger := logging.LoggerFromContext(ctx)
+
+			srv := server.New(&server.Options{
+				Logger:     &logger,
+				Address:    opts.Address,
+				Build:      buildMarkdownDocumentation(opts, &logger, cmd.ErrOrStderr()),
+				LiveReload: !opts.NoReload,
+			})
+
+			logger.Info("Parsing source code for @fyi error definitions ⚙️",
+				"directories", opts.IncludedDirs,
+			)
+			if err := srv.Rebuild(ctx); err != nil {
+				logger.Warn(errors.Annotate(err, "failed to build the application(s) error documentation"))
+			}
+
+			if !opts.NoReload {
+				w, err := newSourceWatcher(&logger, opts.IncludedDirs, []fragmentPatterns{{dirs: opts.IncludedDirs, patterns: fragment.DefaultPatterns}}, opts.InfoTemplate, opts.ErrorTemplate)
+				if err != nil {
+					return err
+				}
+				go func() {
+					_ = w.Run(ctx, func(files []string) {
+						logger.Info("Source code changed, rebuilding the documentation ⚙️", "files", files)
+						if err := srv.Rebuild(ctx); err != nil {
+							logger.Warn(errors.Annotate(err, "failed to rebuild the application(s) error documentation"))
+						}
+					})
+				}()
+			}
+
+			if err := srv.ListenAndServe(ctx); err != nil {
+				// @fyi.error code serve_listen_error
+				// @fyi.error title Error Starting The Documentation Server
+				// @fyi.error short The documentation server could not listen on the given address.
+				// @fyi.error long The documentation server could not listen on the address passed to --address. Check that the address is valid and no other process is using the port.
+				return fyi.Error(err, "serve_listen_error")
+			}
+			return nil
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
+
+// buildMarkdownDocumentation returns the function used by the server to parse the source code and render the
+// markdown documentation of each application under its own directory, i.e: {name}/index.md.
+// The problems found in the annotations are printed to stderr on each build.
+func buildMarkdownDocumentation(opts *serveoptions.Options, logger *logging.Logger, stderr io.Writer) func(ctx context.Context) (map[string][]byte, error) {
+	return func(ctx context.Context) (map[string][]byte, error) {
+		parserOptions := []options.Option{
+			options.Include(opts.IncludedDirs...),
+			options.Exclude(opts.Exclude...),
+			options.IncludeTests(opts.IncludeTests),
+			options.BuildConstraints("", "", opts.BuildTags...),
+			options.Logger(logger),
+		}
+
+		switch opts.Language {
+		case language.Go:
+			parserOptions = append(parserOptions, options.Go())
+		default:
+			// do nothing
+		}
+
+		p := parser.New(parserOptions...)
+		apps, err := p.Parse(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.Diagnostics().Write(stderr, diagnostic.Text); err != nil {
+			logger.Warn(errors.Annotate(err, "failed to print the problems found in the annotations"))
+		}
+
+		files := make(map[string][]byte)
+		for name, app := range apps {
+			generator := markdown.New(&markdown.Options{
+				Logger:        logger,
+				Output:        name,
+				InfoTmplFile:  opts.InfoTemplate,
+				ErrorTmplFile: opts.ErrorTemplate,
+			})
+			rendered, err := generator.Render(ctx, map[string]any{name: app})
+			if err != nil {
+				return nil, err
+			}
+			for path, body := range rendered {
+				files[path] = body
+			}
+		}
+		return files, nil
+	}
+}
+
+// fragmentPatterns are the glob patterns of the fragment files of a set of included directories
+type fragmentPatterns struct {
+	dirs, patterns []string
+}
+
+// newSourceWatcher returns a watcher notifying about changes to the go source files in the given directories,
+// to their fragment files and to the given template files
+func newSourceWatcher(logger *logging.Logger, dirs []string, fragments []fragmentPatterns, templates ...string) (*watcher.Watcher, error) {
+	watchedTemplates := map[string]struct{}{}
+	var paths []string
+	paths = append(paths, dirs...)
+	for _, tmpl := range templates {
+		if tmpl == "" {
+			continue
+		}
+		abs, err := filepath.Abs(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		watchedTemplates[abs] = struct{}{}
+		// watch the parent directory, editors often replace the file when saving it
+		paths = append(paths, filepath.Dir(abs))
+	}
+
+	w, err := watcher.New(&watcher.Options{
+		Logger: logger,
+		Filter: func(path string) bool {
+			if filepath.Ext(path) == ".go" {
+				return true
+			}
+			for _, f := range fragments {
+				if fragment.Match(f.dirs, f.patterns, path) {
+					return true
+				}
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return false
+			}
+			_, ok := watchedTemplates[abs]
+			return ok
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(paths...); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
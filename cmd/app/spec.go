@@ -1,17 +1,24 @@
 package app
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/spf13/cobra"
 	"github.com/tfadeyi/errors/internal/parser"
 	"github.com/tfadeyi/errors/internal/parser/generate"
+	"github.com/tfadeyi/errors/internal/parser/generate/html"
 	"github.com/tfadeyi/errors/internal/parser/language"
 	"github.com/tfadeyi/errors/internal/parser/options"
 	"io"
+	"path/filepath"
 
 	fyi "github.com/tfadeyi/errors"
 	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
 	specoptions "github.com/tfadeyi/errors/cmd/app/options/spec"
+	"github.com/tfadeyi/errors/internal/cache"
+	"github.com/tfadeyi/errors/internal/changes"
 	"github.com/tfadeyi/errors/internal/logging"
 )
 
@@ -40,64 +47,281 @@ func specGenerateCmd(common *commonoptions.Options) *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := logging.LoggerFromContext(cmd.Context())
+			// the flags were validated, the usage doesn't help with the errors from here on
+			cmd.SilenceUsage = true
 
-			logger.Info("Parsing source code for @fyi error definitions ⚙️",
-				"directories", opts.IncludedDirs,
-			)
-
-			parserOptions := []options.Option{
-				options.Include(opts.IncludedDirs...),
-				options.Logger(&logger),
-				options.Output(opts.OutputFileAndDirectory),
-				options.SourceFile(opts.Source),
-				options.SourceContent(inputReader),
-				options.Watermark(`# Code generated by errctl: https://github.com/tfadeyi/errors.
-# DO NOT EDIT.`),
-				options.CustomManifestInfoTemplate(opts.InfoTemplate),
-				options.CustomManifestErrorTemplate(opts.ErrorTemplate),
+			if opts.Check {
+				return checkTargets(cmd, opts, inputReader, &logger)
 			}
 
-			switch opts.Language {
-			case language.Go:
-				parserOptions = append(parserOptions, options.Go())
-			default:
-				// do nothing
-			}
+			var targets []*generateTarget
+			var result error
+			for _, targetOpts := range opts.Targets {
+				targetLogger := logger
+				if targetOpts.Name != "" {
+					targetLogger = logger.WithName(targetOpts.Name)
+				}
+				target := newGenerateTarget(cmd, targetOpts, inputReader, &targetLogger)
+				targets = append(targets, target)
 
-			switch opts.Format {
-			case generate.Yaml:
-				parserOptions = append(parserOptions, options.YAML(cmd.OutOrStdout()))
-			case generate.Markdown:
-				parserOptions = append(parserOptions, options.Markdown(cmd.OutOrStdout()))
+				if err := target.run(cmd.Context()); err != nil {
+					if !opts.Watch {
+						return err
+					}
+					// keep watching, the next change might fix the error
+					targetLogger.Warn(err)
+					result = err
+				}
 			}
+			if !opts.Watch {
+				return result
+			}
+
+			return watchSourceCode(cmd.Context(), targets, &logger)
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
 
-			// @fyi.error code clean_artefacts_error
-			// @fyi.error title Error Removing Previous Artefacts
-			// @fyi.error short The tool has failed to delete the artefacts from the previous execution.
-			// @fyi.error long The tool has failed to delete the artefacts from the previous execution. Try manually deleting them before running the tool again.
+// checkTargets prints the unified diff of the generated files out of sync with the source code, without writing them.
+// An error is returned if any of the files is out of date.
+func checkTargets(cmd *cobra.Command, opts *specoptions.Options, inputReader io.ReadCloser, logger *logging.Logger) error {
+	stale := 0
+	for _, targetOpts := range opts.Targets {
+		targetLogger := *logger
+		if targetOpts.Name != "" {
+			targetLogger = logger.WithName(targetOpts.Name)
+		}
+		target := newGenerateTarget(cmd, targetOpts, inputReader, &targetLogger)
 
-			p := parser.New(
-				parserOptions...,
-			)
+		files, err := target.check(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Fprint(cmd.OutOrStdout(), file.Diff)
+		}
+		stale += len(files)
+	}
+	if stale == 0 {
+		logger.Info("Generated files are up to date ✅")
+		return nil
+	}
 
-			apps, err := p.Parse(cmd.Context())
-			if err != nil {
-				return errors.Annotate(err, "failed to parse the application(s) error manifests")
-			}
+	// @fyi.error code stale_generated_files
+	// @fyi.error title Stale Generated Files
+	// @fyi.error short The generated files on disk are out of sync with the source code annotations.
+	// @fyi.error long The generated files on disk are out of sync with the source code annotations. Run errctl generate without --check to update them, the printed diff shows the expected changes.
+	return fyi.Error(errors.Errorf("%d generated file(s) are out of date", stale), "stale_generated_files")
+}
 
-			logger.Info("Source code was successfully parsed ✅")
+// generateTarget is a generation target, either configured through the command flags or a configuration file target
+type generateTarget struct {
+	opts   *specoptions.Options
+	parser *parser.Parser
+	logger *logging.Logger
+	// stderr is where the problems found in the annotations are printed
+	stderr io.Writer
+	// apps are the application(s) error manifests of the last parsing
+	apps map[string]any
+}
 
-			if err = p.Generate(cmd.Context(), apps); err != nil {
-				return errors.Annotate(err, "failed to printout the application(s) error manifests")
+func newGenerateTarget(cmd *cobra.Command, opts *specoptions.Options, inputReader io.ReadCloser, logger *logging.Logger) *generateTarget {
+	parserOptions := []options.Option{
+		options.Include(opts.IncludedDirs...),
+		options.Exclude(opts.Exclude...),
+		options.IncludeTests(opts.IncludeTests),
+		options.BuildConstraints("", "", opts.BuildTags...),
+		options.Logger(logger),
+		options.Output(opts.OutputFileAndDirectory),
+		options.SourceFile(opts.Source),
+		options.SourceContent(inputReader),
+		options.Watermark(opts.Watermark),
+		options.CustomManifestInfoTemplate(opts.InfoTemplate),
+		options.CustomManifestErrorTemplate(opts.ErrorTemplate),
+		options.TemplateDir(opts.TemplateDir),
+		options.SourceURL(opts.SourceURL),
+		options.AnnotationPrefixes(opts.LegacyPrefixes...),
+		options.Concurrency(opts.Jobs),
+		options.Fragments(opts.Fragments...),
+	}
+	if opts.Merge {
+		parserOptions = append(parserOptions, options.Merge(opts.Orphans))
+	}
+	if !opts.NoCache {
+		dir, err := cache.Dir()
+		if err != nil {
+			logger.Warn(errors.Annotate(err, "failed to find the parse cache directory, the cache is disabled"))
+		} else {
+			parserOptions = append(parserOptions, options.Cache(dir))
+		}
+	}
+
+	switch opts.Language {
+	case language.Go:
+		parserOptions = append(parserOptions, options.Go())
+	default:
+		// do nothing
+	}
+
+	switch opts.Format {
+	case generate.Yaml:
+		parserOptions = append(parserOptions, options.YAML(cmd.OutOrStdout()))
+	case generate.JSON:
+		parserOptions = append(parserOptions, options.Compact(opts.Compact), options.JSON(cmd.OutOrStdout()))
+	case generate.Markdown:
+		parserOptions = append(parserOptions, options.Markdown(cmd.OutOrStdout()))
+	case generate.HTML:
+		parserOptions = append(parserOptions, options.HTML(cmd.OutOrStdout()))
+	}
+
+	// @fyi.error code clean_artefacts_error
+	// @fyi.error title Error Removing Previous Artefacts
+	// @fyi.error short The tool has failed to delete the artefacts from the previous execution.
+	// @fyi.error long The tool has failed to delete the artefacts from the previous execution. Try manually deleting them before running the tool again.
+
+	return &generateTarget{
+		opts:   opts,
+		parser: parser.New(parserOptions...),
+		logger: logger,
+		stderr: cmd.ErrOrStderr(),
+	}
+}
+
+// run parses the source code and generates the application(s) error manifests
+func (t *generateTarget) run(ctx context.Context) error {
+	t.logger.Info("Parsing source code for @fyi error definitions ⚙️",
+		"directories", t.opts.IncludedDirs,
+	)
+
+	apps, err := t.parser.Parse(ctx)
+	if err != nil {
+		return errors.Annotate(err, "failed to parse the application(s) error manifests")
+	}
+	t.apps = apps
+	if err := t.diagnose(); err != nil {
+		return err
+	}
+
+	t.logger.Info("Source code was successfully parsed ✅")
+	return t.generate(ctx)
+}
+
+// check parses the source code and returns the generated files out of sync with the source code
+func (t *generateTarget) check(ctx context.Context) ([]generate.StaleFile, error) {
+	apps, err := t.parser.Parse(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse the application(s) error manifests")
+	}
+	t.apps = apps
+	if err := t.diagnose(); err != nil {
+		return nil, err
+	}
+	return t.parser.Check(ctx, apps)
+}
+
+// update re-parses the changed files, logs the changes to the error definitions and regenerates the manifests
+func (t *generateTarget) update(ctx context.Context, files []string) {
+	current, err := t.parser.Update(ctx, files...)
+	if err != nil {
+		t.logger.Warn(errors.Annotate(err, "failed to parse the application(s) error manifests"))
+		return
+	}
+
+	summary := changes.Compare(t.apps, current)
+	if len(summary) == 0 {
+		t.logger.Info("No error definitions changed")
+	}
+	for _, change := range summary {
+		t.logger.Info(change.String())
+	}
+	t.apps = current
+	if err := t.diagnose(); err != nil {
+		t.logger.Warn(err)
+		return
+	}
+
+	if err := t.generate(ctx); err != nil {
+		t.logger.Warn(err)
+	}
+}
+
+// diagnose prints the problems found in the annotations by the last parsing.
+// In strict mode an error is returned if any problem was found.
+func (t *generateTarget) diagnose() error {
+	diagnostics := t.parser.Diagnostics()
+	if err := diagnostics.Write(t.stderr, t.opts.DiagnosticsFormat); err != nil {
+		t.logger.Warn(errors.Annotate(err, "failed to print the problems found in the annotations"))
+	}
+	if !t.opts.Strict || len(diagnostics) == 0 {
+		return nil
+	}
+
+	// @fyi.error code strict_diagnostics
+	// @fyi.error title Problems Found In The Annotations
+	// @fyi.error short Problems were found in the source code annotations and --strict was set, the output wasn't generated.
+	// @fyi.error long Problems were found in the source code annotations and --strict was set, the output wasn't generated. The problems are printed with their location and a hint on how to fix them, fix them or run the tool without --strict to only report them.
+	return fyi.Error(errors.Errorf("%s found in the annotations", diagnostics.Summary()), "strict_diagnostics")
+}
+
+func (t *generateTarget) generate(ctx context.Context) error {
+	if err := t.parser.Generate(ctx, t.apps); err != nil {
+		return errors.Annotate(err, "failed to printout the application(s) error manifests")
+	}
+
+	t.logger.Info("Application(s) error manifest were successfully generated ✅")
+	return nil
+}
+
+// watchSourceCode re-parses the changed source files and regenerates the application(s) error manifests of the targets,
+// until the context is cancelled. A summary of the added, removed and changed error codes is logged after each rebuild.
+func watchSourceCode(ctx context.Context, targets []*generateTarget, logger *logging.Logger) error {
+	var watched, templates []string
+	var fragments []fragmentPatterns
+	for _, target := range targets {
+		if target.opts.Source != "" {
+			watched = append(watched, target.opts.Source)
+		} else {
+			watched = append(watched, target.opts.IncludedDirs...)
+			fragments = append(fragments, fragmentPatterns{dirs: target.opts.IncludedDirs, patterns: target.opts.Fragments})
+		}
+		templates = append(templates, target.opts.InfoTemplate, target.opts.ErrorTemplate)
+		if target.opts.TemplateDir != "" {
+			// the theme files are watched even if they don't exist yet, so adding an override regenerates the site
+			for _, name := range html.ThemeFiles() {
+				templates = append(templates, filepath.Join(target.opts.TemplateDir, name))
 			}
+		}
+	}
+	w, err := newSourceWatcher(logger, uniqueStrings(watched), fragments, templates...)
+	if err != nil {
+		return errors.Annotate(err, "failed to watch the source code")
+	}
 
-			logger.Info("Application(s) error manifest were successfully generated ✅")
+	logger.Info("Watching the source code for changes 👀", "paths", watched)
+	err = w.Run(ctx, func(files []string) {
+		logger.Info("Source code changed, regenerating the application(s) error manifests ⚙️", "files", files)
+		for _, target := range targets {
+			target.update(ctx, files)
+		}
+	})
+	logger.Info("Stopped watching the source code")
+	return err
+}
 
-			return nil
-		},
+// uniqueStrings returns the values without duplicates, keeping their order
+func uniqueStrings(values []string) []string {
+	seen := map[string]struct{}{}
+	var result []string
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
 	}
-	opts = opts.Prepare(cmd)
-	return cmd
+	return result
 }
 
 func specValidateCmd(common *commonoptions.Options) *cobra.Command {
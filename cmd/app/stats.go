@@ -0,0 +1,92 @@
+package app
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	fyi "github.com/tfadeyi/errors"
+	commonoptions "github.com/tfadeyi/errors/cmd/app/options/common"
+	statsoptions "github.com/tfadeyi/errors/cmd/app/options/stats"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/parser"
+	"github.com/tfadeyi/errors/internal/parser/language"
+	"github.com/tfadeyi/errors/internal/parser/options"
+)
+
+func statsCmd(common *commonoptions.Options) *cobra.Command {
+	opts := statsoptions.New(common)
+
+	cmd := &cobra.Command{
+		Use:          "stats",
+		Short:        "Reports the error annotation coverage of the source code",
+		Long:         ``,
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.LoggerFromContext(cmd.Context())
+			logger = logger.WithName("stats")
+
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			cmd.SetContext(logging.ContextWithLogger(cmd.Context(), logger))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			logger := logging.LoggerFromContext(ctx)
+
+			parserOptions := []options.Option{
+				options.Include(opts.IncludedDirs...),
+				options.Exclude(opts.Exclude...),
+				options.IncludeTests(opts.IncludeTests),
+				options.BuildConstraints("", "", opts.BuildTags...),
+				options.Logger(&logger),
+				options.AnnotationPrefixes(opts.LegacyPrefixes...),
+				options.Concurrency(opts.Jobs),
+				options.Analyze(),
+			}
+
+			switch opts.Language {
+			case language.Go:
+				parserOptions = append(parserOptions, options.Go())
+			default:
+				// do nothing
+			}
+
+			p := parser.New(parserOptions...)
+			if _, err := p.Parse(ctx); err != nil {
+				return errors.Annotate(err, "failed to parse the application(s) error manifests")
+			}
+			if err := p.Diagnostics().Write(cmd.ErrOrStderr(), diagnostic.Text); err != nil {
+				logger.Warn(errors.Annotate(err, "failed to print the problems found in the annotations"))
+			}
+			report, err := p.Stats()
+			if err != nil {
+				return err
+			}
+
+			if opts.Output == "" {
+				return report.Write(cmd.OutOrStdout(), opts.Format)
+			}
+
+			buf := bytes.NewBuffer([]byte{})
+			if err := report.Write(buf, opts.Format); err != nil {
+				return err
+			}
+			// @fyi.error code stats_output_error
+			// @fyi.error title Error Writing The Statistics Report
+			// @fyi.error short The tool has failed to write the statistics report to the file passed to --output.
+			if err := os.WriteFile(opts.Output, buf.Bytes(), 0644); err != nil {
+				return fyi.Error(errors.Annotatef(err, "failed to write the statistics report to %q", opts.Output), "stats_output_error")
+			}
+			logger.Info("Statistics report written", "file", opts.Output)
+			return nil
+		},
+	}
+	opts = opts.Prepare(cmd)
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/tfadeyi/errors/internal/errorclient"
 	"github.com/tfadeyi/errors/internal/errorclient/local"
 	"log"
+	"strings"
 )
 
 type (
@@ -23,6 +24,9 @@ type (
 		// source allows clients to pass the contents of the error specification file as a []byte
 		// WrapperOption: func Manifest(source []byte) WrapperOption
 		source []byte
+		// application selects the manifest of the application in a multi-application manifest stream
+		// WrapperOption: func Application(name string) WrapperOption
+		application string
 		// ErrorDefinitionURLPath is the parent URL path where the errors will be available
 		ErrorDefinitionURLPath string
 		// showErrorURL enables and disables the errors' URL being shown when the error is returned
@@ -34,6 +38,24 @@ type (
 		Options *wrapperOptions
 		client  errorclient.Client
 	}
+
+	// CodedError is an error wrapped with the definition of its code in the application error manifest.
+	// It's returned by Error and ErrorWithContext, use errors.As to read the code and metadata of the error, i.e:
+	//
+	//	var coded *errors.CodedError
+	//	if stderrors.As(err, &coded) {
+	//		owner := coded.Metadata["owner"]
+	//	}
+	CodedError struct {
+		// Code is the code of the error definition
+		Code string
+		// Metadata are the free-form key/value pairs of the error definition, i.e: owner or ticket
+		Metadata map[string]string
+		// err is the wrapped error
+		err error
+		// message is the error message, the wrapped error message together with the error definition
+		message string
+	}
 )
 
 var (
@@ -56,6 +78,7 @@ func New(opts ...WrapperOption) *Wrapper {
 	cl := local.New(errorclient.Options{
 		SourceFilename: wrapper.Options.sourceFilename,
 		Source:         wrapper.Options.source,
+		Application:    wrapper.Options.application,
 	})
 
 	wrapper.client = cl
@@ -68,6 +91,7 @@ func (w *Wrapper) SetManifest(content []byte) {
 	w.client = local.New(errorclient.Options{
 		SourceFilename: w.Options.sourceFilename,
 		Source:         w.Options.source,
+		Application:    w.Options.application,
 	})
 }
 
@@ -76,6 +100,17 @@ func (w *Wrapper) SetManifestFilename(filepath string) {
 	w.client = local.New(errorclient.Options{
 		SourceFilename: w.Options.sourceFilename,
 		Source:         w.Options.source,
+		Application:    w.Options.application,
+	})
+}
+
+// SetApplication selects the manifest of the application when the manifest contains several applications
+func (w *Wrapper) SetApplication(name string) {
+	w.Options.application = name
+	w.client = local.New(errorclient.Options{
+		SourceFilename: w.Options.sourceFilename,
+		Source:         w.Options.source,
+		Application:    w.Options.application,
 	})
 }
 
@@ -104,7 +139,7 @@ func (w *Wrapper) ErrorWithContext(ctx context.Context, err error, code string)
 		return err
 	}
 
-	return fmt.Errorf("%s: [%w]", newErrMessage, err)
+	return w.coded(ctx, err, code, fmt.Sprintf("%s: [%s]", newErrMessage, err))
 }
 
 // Error wraps the incoming error with error defined by the application error manifest according to the input code.
@@ -119,7 +154,29 @@ func (w *Wrapper) Error(err error, code string) error {
 		return err
 	}
 
-	return fmt.Errorf("[%w]\n%s", err, newErrMessage)
+	return w.coded(context.Background(), err, code, fmt.Sprintf("[%s]\n%s", err, newErrMessage))
+}
+
+// coded returns the CodedError wrapping err with the given message
+func (w *Wrapper) coded(ctx context.Context, err error, code, message string) *CodedError {
+	coded := &CodedError{Code: strings.TrimSpace(code), err: err, message: message}
+	if definition, _ := w.client.ErrorDefinitionFromCode(ctx, code); definition != nil && len(definition.Metadata) > 0 {
+		coded.Metadata = make(map[string]string, len(definition.Metadata))
+		for key, value := range definition.Metadata {
+			coded.Metadata[key] = value
+		}
+	}
+	return coded
+}
+
+// Error returns the wrapped error message together with the error definition
+func (e *CodedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the wrapped error
+func (e *CodedError) Unwrap() error {
+	return e.err
 }
 
 func (w *Wrapper) log(msg string, keyVal ...any) {
@@ -138,6 +195,11 @@ func SetManifestFilename(filepath string) {
 	global.SetManifestFilename(filepath)
 }
 
+// SetApplication selects the manifest of the application when the manifest contains several applications
+func SetApplication(name string) {
+	global.SetApplication(name)
+}
+
 func SetLogger(logger *log.Logger) {
 	global.SetLogger(logger)
 }
@@ -176,6 +238,14 @@ func ManifestFilename(filename string) WrapperOption {
 	}
 }
 
+// Application selects the manifest of the application when the manifest contains several applications, i.e: the
+// multi-document manifest generated by errctl
+func Application(name string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.application = name
+	}
+}
+
 func Logger(logger *log.Logger) WrapperOption {
 	return func(o *wrapperOptions) {
 		o.logger = logger
@@ -1 +1,83 @@
 package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = `base_url: https://example.com
+name: app
+version: v0.1.0
+errors_definitions:
+  not_found:
+    code: not_found
+    title: Not Found
+    short: The resource was not found
+    metadata:
+      owner: team-storage
+      ticket: https://example.com/tickets/1
+  no_metadata:
+    code: no_metadata
+    title: No Metadata
+    short: The error has no metadata
+`
+
+func TestCodedError(t *testing.T) {
+	t.Parallel()
+
+	cause := stderrors.New("open file")
+
+	t.Run("Successfully expose the code and metadata of the wrapped error", func(t *testing.T) {
+		t.Parallel()
+		err := New(Manifest([]byte(manifest))).Error(cause, "not_found")
+		assert.Equal(t, "[open file]\n* The resource was not found.", err.Error())
+		assert.ErrorIs(t, err, cause)
+
+		var coded *CodedError
+		require.ErrorAs(t, err, &coded)
+		assert.Equal(t, "not_found", coded.Code)
+		assert.Equal(t, map[string]string{"owner": "team-storage", "ticket": "https://example.com/tickets/1"}, coded.Metadata)
+	})
+	t.Run("Successfully wrap the error with context", func(t *testing.T) {
+		t.Parallel()
+		err := New(Manifest([]byte(manifest))).ErrorWithContext(context.Background(), cause, "no_metadata")
+		assert.Equal(t, "* The error has no metadata.: [open file]", err.Error())
+		assert.ErrorIs(t, err, cause)
+
+		var coded *CodedError
+		require.ErrorAs(t, err, &coded)
+		assert.Equal(t, "no_metadata", coded.Code)
+		assert.Nil(t, coded.Metadata)
+	})
+	t.Run("Successfully read the code of a JSON manifest", func(t *testing.T) {
+		t.Parallel()
+		manifest := `{"base_url": "https://example.com", "name": "app", "version": "v0.1.0", "errors_definitions": {
+			"not_found": {"code": "not_found", "title": "Not Found", "short": "The resource was not found", "metadata": {"owner": "team-storage"}}
+		}}`
+		err := New(Manifest([]byte(manifest))).Error(cause, "not_found")
+		assert.Equal(t, "[open file]\n* The resource was not found.", err.Error())
+
+		var coded *CodedError
+		require.ErrorAs(t, err, &coded)
+		assert.Equal(t, map[string]string{"owner": "team-storage"}, coded.Metadata)
+	})
+	t.Run("Successfully read the code of the selected application of a manifest stream", func(t *testing.T) {
+		t.Parallel()
+		stream := "---\nname: api\nerrors_definitions:\n  timeout:\n    code: timeout\n    short: The request timed out\n---\n" + manifest
+		err := New(Manifest([]byte(stream)), Application("app")).Error(cause, "not_found")
+		assert.Equal(t, "[open file]\n* The resource was not found.", err.Error())
+
+		// the application has to be selected, the codes of the other applications aren't found
+		assert.Equal(t, cause, New(Manifest([]byte(stream))).Error(cause, "not_found"))
+		assert.Equal(t, cause, New(Manifest([]byte(stream)), Application("app")).Error(cause, "timeout"))
+	})
+	t.Run("Fail to wrap the error with an unknown code", func(t *testing.T) {
+		t.Parallel()
+		err := New(Manifest([]byte(manifest))).Error(cause, "unknown")
+		assert.Equal(t, cause, err)
+	})
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// ErrInvalidated is returned by Open when the cache was written with a different key, i.e: by another errctl version
+var ErrInvalidated = errors.New("the cache was written by a different version of the tool or grammar")
+
+type (
+	// Cache stores the partial manifests and diagnostics evaluated from the annotations of each source file, so the files that didn't
+	// change since the previous run don't have to be parsed again. It's safe to use concurrently.
+	Cache struct {
+		path string
+		key  string
+
+		mu      sync.Mutex
+		entries map[string]*Entry
+		// used are the filenames looked up or stored since the cache was opened, the others are dropped on Save
+		used  map[string]bool
+		dirty bool
+	}
+
+	// Entry is the cached content of a source file
+	Entry struct {
+		Size int64
+		// Hash is the hex encoded sha256 digest of the file content
+		Hash string
+		// Annotations are the partial manifests of the annotated comment groups of the file, in source order
+		Annotations []*api.Manifest
+		// Diagnostics are the problems found in the annotations of the file, they aren't located in a file
+		Diagnostics diagnostic.List
+	}
+
+	// file is the on-disk representation of the cache
+	file struct {
+		Key     string
+		Entries map[string]*Entry
+	}
+)
+
+// Dir returns the default directory of the caches, in the user cache directory
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "errctl"), nil
+}
+
+// Key returns the key the caches are invalidated by, built from the given components,
+// i.e: the tool version and the grammar fingerprint.
+func Key(components ...string) string {
+	return strings.Join(components, "\x00")
+}
+
+// Filename returns the path of the cache file, in dir, for the given parsed directories
+func Filename(dir string, parsed ...string) string {
+	hash := sha256.New()
+	for _, path := range parsed {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		hash.Write([]byte(path + "\x00"))
+	}
+	return filepath.Join(dir, "parse-"+hex.EncodeToString(hash.Sum(nil))[:16]+".gob")
+}
+
+// Open reads the cache file at path. A new empty cache is returned, together with the error, if the file doesn't
+// exist, can't be decoded or was written with a different key.
+func Open(path, key string) (*Cache, error) {
+	c := &Cache{
+		path:    path,
+		key:     key,
+		entries: map[string]*Entry{},
+		used:    map[string]bool{},
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	var content file
+	if err := gob.NewDecoder(bytes.NewReader(body)).Decode(&content); err != nil {
+		return c, errors.Annotatef(err, "failed to decode the cache %q", path)
+	}
+	if content.Key != key {
+		c.dirty = true
+		return c, ErrInvalidated
+	}
+	if content.Entries != nil {
+		c.entries = content.Entries
+	}
+	return c, nil
+}
+
+// Hash returns the digest of the content, as stored in Entry.Hash
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached entry of the file, if its size and hash didn't change since it was stored
+func (c *Cache) Get(filename string, size int64, hash string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[filename]
+	if !ok || entry.Size != size || entry.Hash != hash {
+		return nil, false
+	}
+	c.used[filename] = true
+	return entry, true
+}
+
+// Put stores the entry of the file
+func (c *Cache) Put(filename string, entry *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[filename] = entry
+	c.used[filename] = true
+	c.dirty = true
+}
+
+// Remove drops the cached annotations of the file, i.e: when it's deleted
+func (c *Cache) Remove(filename string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[filename]; ok {
+		delete(c.entries, filename)
+		c.dirty = true
+	}
+	delete(c.used, filename)
+}
+
+// Save writes the cache to disk, if it changed. The entries of the files which weren't looked up or stored since the
+// cache was opened are dropped, they were deleted or are no longer parsed.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for filename := range c.entries {
+		if !c.used[filename] {
+			delete(c.entries, filename)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(file{Key: c.key, Entries: c.entries}); err != nil {
+		return errors.Annotate(err, "failed to encode the cache")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so concurrent runs never read a partially written cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	title := "Title"
+	annotations := []*api.Manifest{
+		{Name: "app", Title: &title, ErrorsDefinitions: api.ErrorDefinitions{}},
+		{ErrorsDefinitions: api.ErrorDefinitions{
+			"code": {Code: "code", Title: "Title", Short: "Short.", Solutions: api.Solutions{}},
+		}},
+	}
+	content := []byte("package main")
+	diagnostics := diagnostic.List{{Severity: diagnostic.Warning, Line: 1, Column: 4, Message: "unknown key"}}
+	entry := &Entry{Size: int64(len(content)), Hash: Hash(content), Annotations: annotations, Diagnostics: diagnostics}
+
+	t.Run("Successfully reuse the annotations of unchanged files", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "errctl", "cache.gob")
+
+		c, err := Open(path, "v1")
+		require.Error(t, err)
+		c.Put("main.go", entry)
+		c.Put("deleted.go", &Entry{Size: 1, Hash: Hash([]byte("x"))})
+		require.NoError(t, c.Save())
+
+		c, err = Open(path, "v1")
+		require.NoError(t, err)
+		cached, ok := c.Get("main.go", int64(len(content)), Hash(content))
+		require.True(t, ok)
+		assert.Equal(t, annotations, cached.Annotations)
+		assert.Equal(t, diagnostics, cached.Diagnostics)
+
+		_, ok = c.Get("main.go", int64(len(content)), Hash([]byte("package app")))
+		assert.False(t, ok, "changed files are not cached")
+		require.NoError(t, c.Save())
+
+		c, err = Open(path, "v1")
+		require.NoError(t, err)
+		_, ok = c.Get("deleted.go", 1, Hash([]byte("x")))
+		assert.False(t, ok, "unused entries are dropped on save")
+	})
+
+	t.Run("Successfully invalidate the cache when the key changes", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "cache.gob")
+
+		c, _ := Open(path, "v1")
+		c.Put("main.go", entry)
+		require.NoError(t, c.Save())
+
+		c, err := Open(path, "v2")
+		assert.ErrorIs(t, err, ErrInvalidated)
+		_, ok := c.Get("main.go", int64(len(content)), Hash(content))
+		assert.False(t, ok)
+	})
+
+	t.Run("Successfully name the cache files after the parsed directories", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, Filename("dir", "a", "b"), Filename("dir", "a", "b"))
+		assert.NotEqual(t, Filename("dir", "a", "b"), Filename("dir", "a"))
+	})
+}
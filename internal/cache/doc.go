@@ -0,0 +1,2 @@
+// Package cache stores the annotations parsed from each source file on disk, so unchanged files are skipped by the following runs
+package cache
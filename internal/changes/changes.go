@@ -0,0 +1,100 @@
+package changes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// Change contains the error codes added, removed or changed in an application error manifest
+type Change struct {
+	Application string
+	Added       []string
+	Removed     []string
+	Changed     []string
+}
+
+// Empty checks if the application error definitions didn't change
+func (c Change) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// String returns a concise summary of the change, i.e: "errctl: +1 added (code), -0 removed, ~0 changed"
+func (c Change) String() string {
+	var parts []string
+	for _, group := range []struct {
+		symbol, verb string
+		codes        []string
+	}{
+		{"+", "added", c.Added},
+		{"-", "removed", c.Removed},
+		{"~", "changed", c.Changed},
+	} {
+		part := fmt.Sprintf("%s%d %s", group.symbol, len(group.codes), group.verb)
+		if len(group.codes) > 0 {
+			part += " (" + strings.Join(group.codes, ", ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return c.Application + ": " + strings.Join(parts, ", ")
+}
+
+// Compare returns the changes, sorted by application name, between the previous and current specifications.
+// Applications without changes are omitted. Only the error definitions are compared, their metadata is ignored,
+// so moving an annotation around the source code isn't reported as a change.
+func Compare(previous, current map[string]any) []Change {
+	names := map[string]struct{}{}
+	for name := range previous {
+		names[name] = struct{}{}
+	}
+	for name := range current {
+		names[name] = struct{}{}
+	}
+
+	var result []Change
+	for name := range names {
+		change := compareDefinitions(name, definitions(previous[name]), definitions(current[name]))
+		if !change.Empty() {
+			result = append(result, change)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Application < result[j].Application
+	})
+	return result
+}
+
+func definitions(spec any) api.ErrorDefinitions {
+	manifest, ok := spec.(*api.Manifest)
+	if !ok || manifest == nil {
+		return nil
+	}
+	return manifest.ErrorsDefinitions
+}
+
+func compareDefinitions(name string, previous, current api.ErrorDefinitions) Change {
+	change := Change{Application: name}
+	for code, definition := range current {
+		old, ok := previous[code]
+		if !ok {
+			change.Added = append(change.Added, code)
+			continue
+		}
+		old.Meta, definition.Meta = nil, nil
+		if !reflect.DeepEqual(old, definition) {
+			change.Changed = append(change.Changed, code)
+		}
+	}
+	for code := range previous {
+		if _, ok := current[code]; !ok {
+			change.Removed = append(change.Removed, code)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Strings(change.Changed)
+	return change
+}
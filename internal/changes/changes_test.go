@@ -0,0 +1,53 @@
+package changes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func manifest(name string, definitions api.ErrorDefinitions) *api.Manifest {
+	return &api.Manifest{Name: name, ErrorsDefinitions: definitions}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	previous := map[string]any{
+		"cli": manifest("cli", api.ErrorDefinitions{
+			"kept":    {Code: "kept", Short: "kept", Meta: &api.ErrorMeta{Loc: &api.ErrorMetaLoc{Path: "a.go"}}},
+			"changed": {Code: "changed", Short: "before"},
+			"removed": {Code: "removed", Short: "removed"},
+		}),
+		"unchanged": manifest("unchanged", api.ErrorDefinitions{
+			"code": {Code: "code", Short: "code"},
+		}),
+	}
+	current := map[string]any{
+		"cli": manifest("cli", api.ErrorDefinitions{
+			"kept":    {Code: "kept", Short: "kept", Meta: &api.ErrorMeta{Loc: &api.ErrorMetaLoc{Path: "b.go"}}},
+			"changed": {Code: "changed", Short: "after"},
+			"added":   {Code: "added", Short: "added"},
+		}),
+		"unchanged": manifest("unchanged", api.ErrorDefinitions{
+			"code": {Code: "code", Short: "code"},
+		}),
+		"new": manifest("new", api.ErrorDefinitions{
+			"code": {Code: "code", Short: "code"},
+		}),
+	}
+
+	assert.Equal(t, []Change{
+		{Application: "cli", Added: []string{"added"}, Removed: []string{"removed"}, Changed: []string{"changed"}},
+		{Application: "new", Added: []string{"code"}},
+	}, Compare(previous, current))
+	assert.Empty(t, Compare(current, current))
+}
+
+func TestChangeString(t *testing.T) {
+	t.Parallel()
+
+	change := Change{Application: "cli", Added: []string{"a", "b"}, Changed: []string{"c"}}
+	assert.Equal(t, "cli: +2 added (a, b), -0 removed, ~1 changed (c)", change.String())
+}
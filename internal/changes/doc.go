@@ -0,0 +1,2 @@
+// Package changes summarises the differences between two parsings of the application error manifests
+package changes
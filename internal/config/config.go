@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/module"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Config is the errctl project configuration
+	Config struct {
+		// Path is the path of the loaded configuration file
+		Path string `yaml:"-"`
+		// Targets are the named generation targets run by errctl generate
+		Targets map[string]*Target `yaml:"targets"`
+	}
+
+	// Target is a named generation target, i.e: the yaml manifest or the markdown docs.
+	// Relative paths are resolved against the directory of the configuration file.
+	Target struct {
+		// Name is the name of the target, it is set from the targets key
+		Name     string   `yaml:"-"`
+		Format   string   `yaml:"format,omitempty"`
+		Output   string   `yaml:"output,omitempty"`
+		Language string   `yaml:"language,omitempty"`
+		Include  []string `yaml:"include,omitempty"`
+		Exclude  []string `yaml:"exclude,omitempty"`
+		// Tests includes the _test.go files, nil keeps the default
+		Tests *bool `yaml:"tests,omitempty"`
+		// Tags are the build tags considered satisfied while selecting the files to parse
+		Tags          []string `yaml:"tags,omitempty"`
+		InfoTemplate  string   `yaml:"info_template,omitempty"`
+		ErrorTemplate string   `yaml:"error_template,omitempty"`
+		// TemplateDir is the directory of the files overriding the default theme of the HTML site
+		TemplateDir string `yaml:"template_dir,omitempty"`
+		// SourceURL is the repository URL template the errors are linked to their source with,
+		// i.e: https://github.com/org/repo/blob/main/{path}#L{line}
+		SourceURL string `yaml:"source_url,omitempty"`
+		// Watermark is the header of the generated files, nil keeps the default header
+		Watermark *string `yaml:"watermark,omitempty"`
+		// Fragments are the glob patterns, relative to the included directories, of the fragment files completing
+		// the errors of the annotations, nil keeps the default patterns
+		Fragments []string `yaml:"fragments,omitempty"`
+		// Merge merges the generated YAML manifest into the existing output file, nil keeps the default
+		Merge *bool `yaml:"merge,omitempty"`
+		// Orphans is how the merge handles the errors missing from the source code, mark or drop
+		Orphans string `yaml:"orphans,omitempty"`
+		// Compact prints each JSON manifest on a single line, nil keeps the default
+		Compact *bool `yaml:"compact,omitempty"`
+	}
+)
+
+const (
+	// Filename is the name of the errctl project configuration file
+	Filename = ".errctl.yaml"
+)
+
+var (
+	ErrNotFound      = errors.New("no " + Filename + " configuration file was found")
+	ErrUnknownTarget = errors.New("the generation target is not defined in the configuration file")
+	ErrNoTargets     = errors.New("the configuration file doesn't define any generation target")
+)
+
+// Find returns the path of the configuration file closest to the given directory, looking in the directory and its
+// parents up to the root of the go module. Only the given directory is searched if it isn't part of a go module.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	root, err := module.FindRoot(dir)
+	if err != nil {
+		root = dir
+	}
+
+	for {
+		path := filepath.Join(dir, Filename)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return "", ErrNotFound
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates the configuration file at the given path
+func Load(path string) (*Config, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to read the configuration file %q", path)
+	}
+
+	cfg := new(Config)
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, errors.Annotatef(err, "failed to decode the configuration file %q", path)
+	}
+	if len(cfg.Targets) == 0 {
+		return nil, errors.Annotatef(ErrNoTargets, "%q", path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Path = abs
+	base := filepath.Dir(abs)
+
+	for name, target := range cfg.Targets {
+		if target == nil {
+			target = new(Target)
+			cfg.Targets[name] = target
+		}
+		target.Name = name
+		target.Output = resolve(base, target.Output)
+		target.InfoTemplate = resolve(base, target.InfoTemplate)
+		target.ErrorTemplate = resolve(base, target.ErrorTemplate)
+		target.TemplateDir = resolve(base, target.TemplateDir)
+		for i, dir := range target.Include {
+			target.Include[i] = resolve(base, dir)
+		}
+		if len(target.Include) == 0 {
+			// by default the targets parse the project the configuration file belongs to
+			target.Include = []string{base}
+		}
+	}
+	return cfg, nil
+}
+
+// Select returns the targets with the given name, or all the targets sorted by name if no name is given
+func (c *Config) Select(name string) ([]*Target, error) {
+	if name != "" {
+		target, ok := c.Targets[name]
+		if !ok {
+			return nil, errors.Annotatef(ErrUnknownTarget, "target %q in %q", name, c.Path)
+		}
+		return []*Target{target}, nil
+	}
+
+	targets := make([]*Target, 0, len(c.Targets))
+	for _, target := range c.Targets {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, nil
+}
+
+// resolve returns the path relative to the base directory, empty and absolute paths are returned as they are
+func resolve(base, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully find the configuration file in a parent directory", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n")
+		writeFile(t, filepath.Join(root, Filename), "targets: {}\n")
+		dir := filepath.Join(root, "cmd", "app")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+
+		path, err := Find(dir)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, Filename), path)
+	})
+	t.Run("Fail to find a configuration file outside the module root", func(t *testing.T) {
+		parent := t.TempDir()
+		writeFile(t, filepath.Join(parent, Filename), "targets: {}\n")
+		root := filepath.Join(parent, "module")
+		writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n")
+
+		_, err := Find(root)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully load the targets resolving their paths", func(t *testing.T) {
+		root := t.TempDir()
+		path := filepath.Join(root, Filename)
+		writeFile(t, path, `targets:
+  manifest:
+    format: yaml
+    output: cmd/errors.yaml
+    include: [cmd]
+    exclude: ["**/testdata"]
+    watermark: "# generated"
+  docs:
+    format: markdown
+    output: docs
+    error_template: templates/error.tmpl
+    template_dir: templates/theme
+`)
+
+		cfg, err := Load(path)
+		require.NoError(t, err)
+
+		targets, err := cfg.Select("")
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+
+		docs, manifest := targets[0], targets[1]
+		assert.Equal(t, "docs", docs.Name)
+		assert.Equal(t, filepath.Join(root, "docs"), docs.Output)
+		assert.Equal(t, filepath.Join(root, "templates", "error.tmpl"), docs.ErrorTemplate)
+		assert.Equal(t, filepath.Join(root, "templates", "theme"), docs.TemplateDir)
+		assert.Empty(t, manifest.TemplateDir)
+		assert.Equal(t, []string{root}, docs.Include)
+		assert.Nil(t, docs.Watermark)
+
+		assert.Equal(t, "manifest", manifest.Name)
+		assert.Equal(t, filepath.Join(root, "cmd", "errors.yaml"), manifest.Output)
+		assert.Equal(t, []string{filepath.Join(root, "cmd")}, manifest.Include)
+		assert.Equal(t, []string{"**/testdata"}, manifest.Exclude)
+		require.NotNil(t, manifest.Watermark)
+		assert.Equal(t, "# generated", *manifest.Watermark)
+
+		selected, err := cfg.Select("docs")
+		require.NoError(t, err)
+		assert.Equal(t, []*Target{docs}, selected)
+
+		_, err = cfg.Select("unknown")
+		assert.ErrorIs(t, err, ErrUnknownTarget)
+	})
+	t.Run("Fail to load a configuration file with unknown fields", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), Filename)
+		writeFile(t, path, "targets:\n  docs:\n    formats: markdown\n")
+
+		_, err := Load(path)
+		assert.Error(t, err)
+	})
+	t.Run("Fail to load a configuration file without targets", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), Filename)
+		writeFile(t, path, "targets: {}\n")
+
+		_, err := Load(path)
+		assert.ErrorIs(t, err, ErrNoTargets)
+	})
+}
@@ -0,0 +1,2 @@
+// Package config loads the errctl project configuration file, .errctl.yaml, describing the generation targets of a project
+package config
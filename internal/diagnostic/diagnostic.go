@@ -0,0 +1,180 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Severity is the severity of a diagnostic, i.e: error
+	Severity string
+
+	// Diagnostic is a problem found in the source code annotations
+	Diagnostic struct {
+		Severity Severity `json:"severity"`
+		// Filename, Line and Column are the location of the problem, they are left empty if unknown
+		Filename string `json:"file,omitempty"`
+		Line     int    `json:"line,omitempty"`
+		Column   int    `json:"column,omitempty"`
+		Message  string `json:"message"`
+		// Hint suggests how to fix the problem
+		Hint string `json:"hint,omitempty"`
+	}
+
+	// List is a list of diagnostics
+	List []Diagnostic
+)
+
+const (
+	// Error diagnostics are annotations that couldn't be parsed, the errors they define are missing from the manifests
+	Error Severity = "error"
+	// Warning diagnostics are annotations that were parsed but are likely incorrect or incomplete
+	Warning Severity = "warning"
+)
+
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+// IsSupportedFormat checks if the given diagnostics format is a supported one
+func IsSupportedFormat(format string) bool {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case Text, JSON:
+		return true
+	}
+	return false
+}
+
+// Position returns the location of the diagnostic, i.e: main.go:10:4, relative to the working directory if possible
+func (d Diagnostic) Position() string {
+	if d.Filename == "" {
+		return ""
+	}
+	position := relative(d.Filename)
+	if d.Line > 0 {
+		position += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			position += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return position
+}
+
+// String returns the diagnostic in the file:line:col: severity: message format
+func (d Diagnostic) String() string {
+	text := string(d.Severity) + ": " + d.Message
+	if position := d.Position(); position != "" {
+		text = position + ": " + text
+	}
+	return text
+}
+
+// Count returns the number of diagnostics with the given severity
+func (l List) Count(severity Severity) int {
+	count := 0
+	for _, d := range l {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Sort orders the diagnostics by location, so the output is stable
+func (l List) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		if l[i].Filename != l[j].Filename {
+			return l[i].Filename < l[j].Filename
+		}
+		if l[i].Line != l[j].Line {
+			return l[i].Line < l[j].Line
+		}
+		return l[i].Column < l[j].Column
+	})
+}
+
+// Summary returns the number of errors and warnings, i.e: 2 problems (1 error, 1 warning)
+func (l List) Summary() string {
+	return fmt.Sprintf("%d %s (%d %s, %d %s)",
+		len(l), plural(len(l), "problem"),
+		l.Count(Error), plural(l.Count(Error), "error"),
+		l.Count(Warning), plural(l.Count(Warning), "warning"),
+	)
+}
+
+// Write writes the diagnostics to the writer in the given format (text, json)
+func (l List) Write(w io.Writer, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case JSON:
+		return l.writeJSON(w)
+	default:
+		return l.writeText(w)
+	}
+}
+
+func (l List) writeJSON(w io.Writer) error {
+	diagnostics := l
+	if diagnostics == nil {
+		diagnostics = List{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Diagnostics List `json:"diagnostics"`
+		Errors      int  `json:"errors"`
+		Warnings    int  `json:"warnings"`
+	}{
+		Diagnostics: diagnostics,
+		Errors:      l.Count(Error),
+		Warnings:    l.Count(Warning),
+	})
+}
+
+func (l List) writeText(w io.Writer) error {
+	if len(l) == 0 {
+		return nil
+	}
+	for _, d := range l {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+		if d.Hint != "" {
+			if _, err := fmt.Fprintf(w, "    hint: %s\n", d.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, l.Summary())
+	return err
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// relative returns the path relative to the working directory, if it's under it
+func relative(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
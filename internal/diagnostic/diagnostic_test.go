@@ -0,0 +1,67 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	list := List{
+		{Severity: Warning, Filename: "main.go", Line: 12, Column: 4, Message: "unknown key \"titel\"", Hint: "the supported keys are: title"},
+		{Severity: Error, Filename: "main.go", Line: 3, Column: 1, Message: "invalid annotations"},
+		{Severity: Error, Filename: "api.go", Message: "the file can't be parsed"},
+	}
+	list.Sort()
+
+	t.Run("Successfully sort the diagnostics by location", func(t *testing.T) {
+		t.Parallel()
+		var positions []string
+		for _, d := range list {
+			positions = append(positions, d.Position())
+		}
+		assert.Equal(t, []string{"api.go", "main.go:3:1", "main.go:12:4"}, positions)
+	})
+
+	t.Run("Successfully write the diagnostics as text", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, list.Write(&buf, Text))
+		assert.Equal(t, `api.go: error: the file can't be parsed
+main.go:3:1: error: invalid annotations
+main.go:12:4: warning: unknown key "titel"
+    hint: the supported keys are: title
+3 problems (2 errors, 1 warning)
+`, buf.String())
+	})
+
+	t.Run("Successfully write the diagnostics as json", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, list.Write(&buf, JSON))
+		var report struct {
+			Diagnostics List `json:"diagnostics"`
+			Errors      int  `json:"errors"`
+			Warnings    int  `json:"warnings"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+		assert.Equal(t, list, report.Diagnostics)
+		assert.Equal(t, 2, report.Errors)
+		assert.Equal(t, 1, report.Warnings)
+	})
+
+	t.Run("Successfully write no diagnostics", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, List(nil).Write(&buf, Text))
+		assert.Empty(t, buf.String())
+
+		require.NoError(t, List(nil).Write(&buf, JSON))
+		assert.JSONEq(t, `{"diagnostics": [], "errors": 0, "warnings": 0}`, buf.String())
+	})
+}
@@ -0,0 +1,2 @@
+// Package diagnostic describes the problems found in the source code annotations and renders them for the user
+package diagnostic
@@ -0,0 +1,260 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type (
+	operation int
+
+	edit struct {
+		op   operation
+		line string
+	}
+)
+
+const (
+	equal operation = iota
+	insert
+	remove
+
+	// contextLines is the number of unchanged lines shown around each change
+	contextLines = 3
+)
+
+// Unified returns the unified diff between the old and new content, an empty string is returned if they are equal.
+// Use an empty oldName or newName to mark the file as created or deleted, i.e: /dev/null.
+func Unified(oldName, newName string, oldContent, newContent []byte) string {
+	if bytes.Equal(oldContent, newContent) {
+		return ""
+	}
+	if oldName == "" {
+		oldName = "/dev/null"
+	}
+	if newName == "" {
+		newName = "/dev/null"
+	}
+
+	edits := compute(splitLines(oldContent), splitLines(newContent))
+
+	buf := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(edits) {
+		buf.WriteString(h)
+	}
+	return buf.String()
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// compute returns the shortest edit script between a and b, using the Myers diff algorithm.
+// The common prefix and suffix are trimmed before, they're unchanged.
+func compute(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{op: equal, line: line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{op: equal, line: line})
+	}
+	return edits
+}
+
+// myers returns the shortest edit script between a and b. The trace only keeps the diagonals reachable at each step d,
+// the window [-d-1, d+1] of V, so it takes O(D²) memory rather than O((n+m)·D), D being the size of the edit script.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	total := n + m
+	offset := total + 1
+	v := make([]int, 2*total+3)
+	var trace [][]int
+
+	for d := 0; d <= total; d++ {
+		window := make([]int, 2*d+3)
+		copy(window, v[offset-d-1:offset+d+2])
+		trace = append(trace, window)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the trace back from the end of a and b, the V window of step d is offset by d+1
+func backtrack(trace [][]int, a, b []string) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: equal, line: a[x]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{op: insert, line: b[y]})
+		} else {
+			x--
+			edits = append(edits, edit{op: remove, line: a[x]})
+		}
+	}
+
+	// the edits were collected from the end of the files
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// hunks groups the edits into unified diff hunks, surrounded by contextLines of unchanged lines
+func hunks(edits []edit) []string {
+	var result []string
+
+	i := 0
+	oldLine, newLine := 1, 1
+	for i < len(edits) {
+		// skip to the next change
+		if edits[i].op == equal {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		oldStart := oldLine - (i - start)
+		newStart := newLine - (i - start)
+
+		// extend the hunk while the changes are close enough to share their context
+		end := i
+		for end < len(edits) {
+			if edits[end].op != equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == equal {
+				run++
+			}
+			if run == len(edits) || run-end > 2*contextLines {
+				end += minInt(contextLines, run-end)
+				break
+			}
+			end = run
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		oldCount, newCount := 0, 0
+		for _, e := range edits[start:end] {
+			line := e.line
+			noNewline := !strings.HasSuffix(line, "\n")
+			if noNewline {
+				line += "\n"
+			}
+			switch e.op {
+			case equal:
+				body.WriteString(" " + line)
+				oldCount++
+				newCount++
+			case remove:
+				body.WriteString("-" + line)
+				oldCount++
+			case insert:
+				body.WriteString("+" + line)
+				newCount++
+			}
+			if noNewline {
+				body.WriteString("\\ No newline at end of file\n")
+			}
+		}
+
+		for _, e := range edits[i:end] {
+			switch e.op {
+			case equal:
+				oldLine++
+				newLine++
+			case remove:
+				oldLine++
+			case insert:
+				newLine++
+			}
+		}
+		i = end
+
+		result = append(result, fmt.Sprintf("@@ -%s +%s @@\n%s", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount), body.String()))
+	}
+	return result
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// an empty range refers to the line before the change
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
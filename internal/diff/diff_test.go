@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully return an empty diff for equal content", func(t *testing.T) {
+		assert.Empty(t, Unified("a", "b", []byte("one\ntwo\n"), []byte("one\ntwo\n")))
+	})
+	t.Run("Successfully diff a changed line with its context", func(t *testing.T) {
+		old := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n")
+		updated := []byte("1\n2\n3\n4\nfive\n6\n7\n8\n9\n")
+		assert.Equal(t, `--- a/errors.yaml
++++ b/errors.yaml
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`, Unified("a/errors.yaml", "b/errors.yaml", old, updated))
+	})
+	t.Run("Successfully diff a created and a deleted file", func(t *testing.T) {
+		assert.Equal(t, "--- /dev/null\n+++ b/index.md\n@@ -0,0 +1,2 @@\n+# cli\n+\n", Unified("", "b/index.md", nil, []byte("# cli\n\n")))
+		assert.Equal(t, "--- a/index.md\n+++ /dev/null\n@@ -1 +0,0 @@\n-# cli\n", Unified("a/index.md", "", []byte("# cli\n"), nil))
+	})
+	t.Run("Successfully mark the missing newline at the end of the file", func(t *testing.T) {
+		assert.Equal(t, "--- a\n+++ b\n@@ -1 +1 @@\n-one\n+one\n\\ No newline at end of file\n", Unified("a", "b", []byte("one\n"), []byte("one")))
+	})
+	t.Run("Successfully diff large manifests in bounded memory", func(t *testing.T) {
+		var old, updated strings.Builder
+		for i := 0; i < 20000; i++ {
+			fmt.Fprintf(&old, "line %d\n", i)
+			if i%100 == 50 {
+				// a change every 100 lines, the edit script has 400 edits
+				fmt.Fprintf(&updated, "changed %d\n", i)
+				continue
+			}
+			fmt.Fprintf(&updated, "line %d\n", i)
+		}
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		diff := Unified("a", "b", []byte(old.String()), []byte(updated.String()))
+		runtime.ReadMemStats(&after)
+
+		assert.Equal(t, 200, strings.Count(diff, "\n@@ "))
+		assert.Contains(t, diff, "@@ -48,7 +48,7 @@\n line 47\n line 48\n line 49\n-line 50\n+changed 50\n line 51\n")
+		// storing the whole V array at each step takes about 256MB
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+	})
+}
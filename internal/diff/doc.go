@@ -0,0 +1,2 @@
+// Package diff computes unified diffs between the content on disk and the content generated by errctl
+package diff
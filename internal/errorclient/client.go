@@ -2,11 +2,15 @@ package errorclient
 
 import (
 	"context"
+
+	"github.com/tfadeyi/errors/pkg/api"
 )
 
 type (
 	Client interface {
 		GenerateErrorMessageFromCode(ctx context.Context, code string) (string, error)
+		// ErrorDefinitionFromCode returns the definition of the error code in the application error manifest
+		ErrorDefinitionFromCode(ctx context.Context, code string) (*api.Error, error)
 	}
 
 	// Options for the error handler. Use it to configure the aloe error handler
@@ -15,6 +19,9 @@ type (
 		SourceFilename string
 		// Source is the in-memory error specification for the target service
 		Source []byte
+		// Application is the name of the application whose manifest is selected from a multi-application manifest
+		// stream, it can be left empty if the manifest contains a single application
+		Application string
 		// ErrorDefinitionURLPath is the parent URL path where the errors will be available
 		ErrorDefinitionURLPath string
 		// ShowErrorURLs enables and disables the errors' URL being shown when the error is returned
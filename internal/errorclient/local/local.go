@@ -1,10 +1,14 @@
 package local
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/tfadeyi/errors/internal/errorclient"
@@ -21,6 +25,7 @@ type (
 
 var (
 	ErrSpecificationDoesNotExist = errors.New("specification file doesn't exist")
+	ErrEmptyManifest             = errors.New("the manifest doesn't define any application")
 )
 
 const (
@@ -37,16 +42,111 @@ func New(opts errorclient.Options) errorclient.Client {
 	}
 }
 
-func decodeSpec(buf []byte) (*api.Manifest, error) {
-	var spec api.Manifest
-	var err = yaml.Unmarshal(buf, &spec)
-	return &spec, err
+// DecodeManifest decodes the manifest of the application from the YAML or JSON manifest stream, see DecodeManifests.
+// The application can be left empty if the stream contains a single manifest.
+func DecodeManifest(buf []byte, application string) (*api.Manifest, error) {
+	manifests, err := DecodeManifests(buf)
+	if err != nil {
+		return nil, err
+	}
+	if application == "" && len(manifests) == 1 {
+		return manifests[0], nil
+	}
+	var names []string
+	for _, manifest := range manifests {
+		if application != "" && manifest.Name == application {
+			return manifest, nil
+		}
+		names = append(names, strconv.Quote(manifest.Name))
+	}
+	switch {
+	case len(manifests) == 0:
+		return nil, ErrEmptyManifest
+	case application == "":
+		return nil, fmt.Errorf("the manifest defines several applications (%s), the application has to be selected by name", strings.Join(names, ", "))
+	default:
+		return nil, fmt.Errorf("the manifest doesn't define the application %q, it defines %s", application, strings.Join(names, ", "))
+	}
+}
+
+// DecodeManifests decodes the application error manifests from a YAML or JSON stream, in stream order.
+// The JSON manifests are detected by their leading brace, the empty YAML documents are skipped.
+func DecodeManifests(buf []byte) ([]*api.Manifest, error) {
+	var manifests []*api.Manifest
+	if isJSON(buf) {
+		decoder := json.NewDecoder(bytes.NewReader(buf))
+		for {
+			spec := new(api.Manifest)
+			err := decoder.Decode(spec)
+			if errors.Is(err, io.EOF) {
+				return manifests, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, spec)
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+			continue
+		}
+		spec := new(api.Manifest)
+		if err := doc.Decode(spec); err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, spec)
+	}
+}
+
+// isJSON checks if the content is a JSON object
+func isJSON(buf []byte) bool {
+	buf = bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf"))
+	buf = bytes.TrimLeft(buf, " \t\r\n")
+	return len(buf) > 0 && buf[0] == '{'
+}
+
+// ErrorURL returns the URL of the error documentation, i.e: {base_url}/{name}/{parentPath}/{code}.
+// If parentPath is empty the default "errors" path is used.
+func ErrorURL(baseURL, name, parentPath, code string) string {
+	if parentPath == "" {
+		parentPath = errorDefinitionPath
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSpace(baseURL), strings.TrimSpace(name), parentPath, code)
 }
 
 func (l *Client) GenerateErrorMessageFromCode(ctx context.Context, code string) (string, error) {
+	code = strings.TrimSpace(code)
+	v, err := l.ErrorDefinitionFromCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(v.Short)
+
+	result := fmt.Sprintf("* %s.", summary)
+	if l.ShowErrorURLs {
+		url := ErrorURL(l.Spec.BaseUrl, l.Spec.Name, l.ErrorDefinitionURLPath, code)
+		result = fmt.Sprintf("%s Additional information is available at %s", result, url)
+	}
+	return result, nil
+}
+
+// ErrorDefinitionFromCode returns the definition of the error code, the manifest is loaded on the first call
+func (l *Client) ErrorDefinitionFromCode(ctx context.Context, code string) (*api.Error, error) {
 	select {
 	case <-ctx.Done():
-		return "", errors.New("termination signal was received, terminating process")
+		return nil, errors.New("termination signal was received, terminating process")
 	default:
 	}
 
@@ -56,33 +156,22 @@ func (l *Client) GenerateErrorMessageFromCode(ctx context.Context, code string)
 		if l.SourceFilename != "" && l.Source == nil {
 			_, err = os.Stat(l.SourceFilename)
 			if errors.Is(err, os.ErrNotExist) {
-				return "", ErrSpecificationDoesNotExist
+				return nil, ErrSpecificationDoesNotExist
 			}
 			l.Source, err = os.ReadFile(l.SourceFilename)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 		}
 
-		if l.Spec, err = decodeSpec(l.Source); err != nil {
-			return "", err
+		if l.Spec, err = DecodeManifest(l.Source, l.Application); err != nil {
+			return nil, err
 		}
 	}
 
-	// build error urls
 	v, ok := l.Spec.ErrorsDefinitions[code]
 	if !ok {
-		return "", errors.New("no error was not found in the error specification file")
+		return nil, errors.New("no error was not found in the error specification file")
 	}
-
-	name := strings.TrimSpace(l.Spec.Name)
-	baseURL := strings.TrimSpace(l.Spec.BaseUrl)
-	summary := strings.TrimSpace(v.Short)
-
-	result := fmt.Sprintf("* %s.", summary)
-	if l.ShowErrorURLs {
-		url := fmt.Sprintf("%s/%s/%s/%s", baseURL, name, l.ErrorDefinitionURLPath, code)
-		result = fmt.Sprintf("%s Additional information is available at %s", result, url)
-	}
-	return result, nil
+	return &v, nil
 }
@@ -0,0 +1,47 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeManifest(t *testing.T) {
+	t.Parallel()
+
+	const (
+		yamlStream = "---\n# Code generated by errctl. DO NOT EDIT.\nname: api\nversion: v1\n---\n# Code generated by errctl. DO NOT EDIT.\nname: web\nversion: v2\n"
+		jsonStream = "{\"base_url\": \"\", \"name\": \"api\", \"version\": \"v1\"}\n{\"base_url\": \"\", \"name\": \"web\", \"version\": \"v2\"}\n"
+	)
+
+	t.Run("Successfully decode every application of a manifest stream", func(t *testing.T) {
+		t.Parallel()
+		for _, stream := range []string{yamlStream, jsonStream} {
+			manifests, err := DecodeManifests([]byte(stream))
+			require.NoError(t, err)
+			require.Len(t, manifests, 2)
+			assert.Equal(t, "api", manifests[0].Name)
+			assert.Equal(t, "web", manifests[1].Name)
+
+			manifest, err := DecodeManifest([]byte(stream), "web")
+			require.NoError(t, err)
+			assert.Equal(t, "v2", manifest.Version)
+		}
+	})
+	t.Run("Successfully decode the only application of a manifest", func(t *testing.T) {
+		t.Parallel()
+		manifest, err := DecodeManifest([]byte("---\n---\nname: api\n"), "")
+		require.NoError(t, err)
+		assert.Equal(t, "api", manifest.Name)
+	})
+	t.Run("Fail to decode a manifest stream without selecting the application", func(t *testing.T) {
+		t.Parallel()
+		_, err := DecodeManifest([]byte(yamlStream), "")
+		assert.EqualError(t, err, `the manifest defines several applications ("api", "web"), the application has to be selected by name`)
+		_, err = DecodeManifest([]byte(jsonStream), "cli")
+		assert.EqualError(t, err, `the manifest doesn't define the application "cli", it defines "api", "web"`)
+		_, err = DecodeManifest([]byte("# empty\n"), "")
+		assert.ErrorIs(t, err, ErrEmptyManifest)
+	})
+}
@@ -0,0 +1,2 @@
+// Package explain resolves error codes against the application error manifests and formats them for the terminal
+package explain
@@ -0,0 +1,309 @@
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tfadeyi/errors/internal/errorclient/local"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+type (
+	// Explanation is the resolved error definition for a given code
+	Explanation struct {
+		Application string         `json:"application"`
+		Code        string         `json:"code"`
+		Title       string         `json:"title"`
+		Short       string         `json:"short"`
+		Long        string         `json:"long,omitempty"`
+		Solutions   []api.Solution `json:"solutions,omitempty"`
+		// Metadata are the free-form key/value pairs of the error definition, i.e: owner
+		Metadata map[string]string `json:"metadata,omitempty"`
+		Location string            `json:"location,omitempty"`
+		URL      string            `json:"url,omitempty"`
+	}
+
+	// RenderOptions contains the configuration options available to the terminal renderer
+	RenderOptions struct {
+		// Color enables ANSI colors in the output
+		Color bool
+		// Width is the column at which the text is wrapped, 0 disables wrapping
+		Width int
+	}
+)
+
+const (
+	maxSuggestions = 3
+)
+
+// Find looks up the error code in the given manifests. The manifests are searched in application name order.
+func Find(manifests map[string]*api.Manifest, code string) (*Explanation, bool) {
+	code = strings.TrimSpace(code)
+	for _, name := range sortedNames(manifests) {
+		manifest := manifests[name]
+		definition, ok := manifest.ErrorsDefinitions[code]
+		if !ok {
+			continue
+		}
+		return newExplanation(manifest, definition), true
+	}
+	return nil, false
+}
+
+func newExplanation(manifest *api.Manifest, definition api.Error) *Explanation {
+	explanation := &Explanation{
+		Application: manifest.Name,
+		Code:        definition.Code,
+		Title:       definition.Title,
+		Short:       definition.Short,
+	}
+	if definition.Long != nil {
+		explanation.Long = *definition.Long
+	}
+	if len(definition.Metadata) > 0 {
+		explanation.Metadata = definition.Metadata
+	}
+	if definition.Meta != nil && definition.Meta.Loc != nil {
+		explanation.Location = definition.Meta.Loc.Path
+		if definition.Meta.Loc.Line != nil {
+			explanation.Location += ":" + strconv.Itoa(*definition.Meta.Loc.Line)
+		}
+	}
+	if manifest.BaseUrl != "" {
+		explanation.URL = local.ErrorURL(manifest.BaseUrl, manifest.Name, "", definition.Code)
+	}
+
+	codes := make([]string, 0, len(definition.Solutions))
+	for code := range definition.Solutions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		explanation.Solutions = append(explanation.Solutions, definition.Solutions[code])
+	}
+	return explanation
+}
+
+// Suggest returns the codes in the given manifests that are the closest match to the unknown code.
+func Suggest(manifests map[string]*api.Manifest, code string) []string {
+	type candidate struct {
+		code     string
+		distance int
+	}
+
+	code = strings.ToLower(strings.TrimSpace(code))
+	seen := map[string]struct{}{}
+	var candidates []candidate
+	for _, name := range sortedNames(manifests) {
+		for known := range manifests[name].ErrorsDefinitions {
+			if _, ok := seen[known]; ok {
+				continue
+			}
+			seen[known] = struct{}{}
+
+			lower := strings.ToLower(known)
+			distance := levenshtein(code, lower)
+			if code != "" && (strings.Contains(lower, code) || strings.Contains(code, lower)) {
+				distance = 0
+			}
+			// only suggest codes that share at least half of their characters with the unknown code
+			if distance > (len(lower)+1)/2 {
+				continue
+			}
+			candidates = append(candidates, candidate{code: known, distance: distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].code < candidates[j].code
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].code)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between the two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+func sortedNames(manifests map[string]*api.Manifest) []string {
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteJSON writes the value, i.e: an Explanation, to the writer as indented JSON
+func WriteJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// Write writes the explanation to the writer formatted for the terminal
+func Write(w io.Writer, explanation *Explanation, opts RenderOptions) error {
+	p := &printer{RenderOptions: opts}
+
+	title := explanation.Title
+	if title == "" {
+		title = explanation.Code
+	}
+	p.line(p.style(bold, title))
+	p.line(p.style(faint, fmt.Sprintf("%s · %s", explanation.Application, explanation.Code)))
+	p.line("")
+	p.paragraph(explanation.Short)
+
+	if explanation.Long != "" {
+		p.line("")
+		p.line(p.style(bold, "Details"))
+		p.paragraph(explanation.Long)
+	}
+
+	if len(explanation.Solutions) > 0 {
+		p.line("")
+		p.line(p.style(bold, "Solutions"))
+		for _, solution := range explanation.Solutions {
+			text := solution.Short
+			if solution.Title != nil && *solution.Title != "" {
+				text = fmt.Sprintf("%s: %s", *solution.Title, text)
+			}
+			p.item(text)
+			if solution.Long != nil && *solution.Long != "" {
+				p.indented(*solution.Long, "    ")
+			}
+		}
+	}
+
+	if len(explanation.Metadata) > 0 {
+		p.line("")
+		keys := make([]string, 0, len(explanation.Metadata))
+		for key := range explanation.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			p.line(fmt.Sprintf("%s %s", p.style(bold, key+":"), explanation.Metadata[key]))
+		}
+	}
+
+	if explanation.Location != "" || explanation.URL != "" {
+		p.line("")
+	}
+	if explanation.Location != "" {
+		p.line(fmt.Sprintf("%s %s", p.style(bold, "Source:"), explanation.Location))
+	}
+	if explanation.URL != "" {
+		p.line(fmt.Sprintf("%s %s", p.style(bold, "Docs:"), p.style(underline, explanation.URL)))
+	}
+
+	_, err := io.WriteString(w, p.String())
+	return err
+}
+
+const (
+	bold      = "1"
+	faint     = "2"
+	underline = "4"
+)
+
+type printer struct {
+	RenderOptions
+	strings.Builder
+}
+
+func (p *printer) style(code, text string) string {
+	if !p.Color {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, text)
+}
+
+func (p *printer) line(text string) {
+	p.WriteString(text)
+	p.WriteString("\n")
+}
+
+func (p *printer) paragraph(text string) {
+	p.indented(text, "")
+}
+
+func (p *printer) item(text string) {
+	lines := wrap(text, p.Width-2)
+	for i, line := range lines {
+		prefix := "  "
+		if i == 0 {
+			prefix = "- "
+		}
+		p.line(prefix + line)
+	}
+}
+
+func (p *printer) indented(text, indent string) {
+	for _, line := range wrap(text, p.Width-len(indent)) {
+		p.line(indent + line)
+	}
+}
+
+// wrap breaks the text into lines no longer than width, keeping the existing line breaks.
+// Words longer than width are kept on their own line.
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 || width <= 0 {
+			lines = append(lines, strings.TrimSpace(paragraph))
+			continue
+		}
+		current := words[0]
+		for _, word := range words[1:] {
+			if len([]rune(current))+1+len([]rune(word)) > width {
+				lines = append(lines, current)
+				current = word
+				continue
+			}
+			current += " " + word
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package explain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	long := "The log level passed to the --log-level flag is not currently supported by the tool."
+	manifests := map[string]*api.Manifest{
+		"cli": {
+			Name:    "cli",
+			BaseUrl: "https://tfadeyi.github.io",
+			ErrorsDefinitions: api.ErrorDefinitions{
+				"invalid_log_level": {
+					Code:     "invalid_log_level",
+					Title:    "Invalid Log-Level Argument",
+					Short:    "The log level passed to the --log-level flag is not supported.",
+					Long:     &long,
+					Meta:     &api.ErrorMeta{Loc: &api.ErrorMetaLoc{Path: "options.go"}},
+					Metadata: api.ErrorMetadata{"owner": "team-cli"},
+					Solutions: api.Solutions{
+						"use_info": {Code: "use_info", Short: "Use the info log level"},
+					},
+				},
+				"invalid_output_format": {
+					Code:  "invalid_output_format",
+					Title: "Invalid Output Format",
+					Short: "The output format passed to --format was invalid.",
+				},
+			},
+		},
+	}
+
+	t.Run("Successfully find the error definition and its documentation URL", func(t *testing.T) {
+		explanation, ok := Find(manifests, " invalid_log_level ")
+		require.True(t, ok)
+		assert.Equal(t, "cli", explanation.Application)
+		assert.Equal(t, "Invalid Log-Level Argument", explanation.Title)
+		assert.Equal(t, long, explanation.Long)
+		assert.Equal(t, "options.go", explanation.Location)
+		assert.Equal(t, "https://tfadeyi.github.io/cli/errors/invalid_log_level", explanation.URL)
+		require.Len(t, explanation.Solutions, 1)
+		assert.Equal(t, "use_info", explanation.Solutions[0].Code)
+		assert.Equal(t, map[string]string{"owner": "team-cli"}, explanation.Metadata)
+	})
+	t.Run("Successfully suggest the closest codes for an unknown code", func(t *testing.T) {
+		_, ok := Find(manifests, "invalid_log_levl")
+		require.False(t, ok)
+		assert.Equal(t, []string{"invalid_log_level"}, Suggest(manifests, "invalid_log_levl"))
+		assert.Equal(t, []string{"invalid_output_format"}, Suggest(manifests, "output_format"))
+		assert.Empty(t, Suggest(manifests, "payment_declined"))
+	})
+	t.Run("Successfully wrap the terminal output at the given width", func(t *testing.T) {
+		explanation, ok := Find(manifests, "invalid_log_level")
+		require.True(t, ok)
+
+		buf := bytes.NewBuffer([]byte{})
+		require.NoError(t, Write(buf, explanation, RenderOptions{Width: 30}))
+		for _, line := range bytes.Split(buf.Bytes(), []byte("\n")) {
+			if bytes.HasPrefix(line, []byte("Docs:")) {
+				continue
+			}
+			assert.LessOrEqual(t, len([]rune(string(line))), 30, string(line))
+		}
+		assert.NotContains(t, buf.String(), "\x1b[")
+		assert.Contains(t, buf.String(), "- Use the info log level")
+	})
+}
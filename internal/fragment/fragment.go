@@ -0,0 +1,519 @@
+package fragment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Fragment is a fragment file, it holds partial error definitions merged by code into the errors defined by
+	// the annotations, i.e:
+	//
+	//	name: app
+	//	errors_definitions:
+	//	  not_found:
+	//	    long: |
+	//	      The resource wasn't found, ...
+	//	    solutions:
+	//	      check_name:
+	//	        short: Check the name of the resource.
+	Fragment struct {
+		Filename string
+		// Name is the application of the errors, they're looked up in every application if empty
+		Name   string
+		Errors []*Error
+	}
+
+	// Error is a partial error definition of a fragment file
+	Error struct {
+		Code         string
+		Line, Column int
+		// Fields are the values of the error fields, in the order they're written
+		Fields []Field
+	}
+
+	// Field is the value of an error field in a fragment file, its path is the field name, i.e: long, or
+	// metadata.<key> and solutions.<code>.<field> for the metadata and solutions
+	Field struct {
+		Path         string
+		Value        string
+		Line, Column int
+	}
+)
+
+// DefaultPatterns are the glob patterns, relative to the included directories, of the fragment files
+var DefaultPatterns = []string{"errors.d/*.yaml", "errors.d/*.yml", "errors.d/*.json"}
+
+const (
+	errorsKey    = "errors_definitions"
+	nameKey      = "name"
+	codeKey      = "code"
+	metadataKey  = "metadata"
+	solutionsKey = "solutions"
+)
+
+// Find returns the fragment files matching the glob patterns in the directories, sorted and without duplicates.
+// Absolute patterns are matched as they are.
+func Find(dirs, patterns []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	var filenames []string
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		globs := []string{pattern}
+		if !filepath.IsAbs(pattern) {
+			globs = globs[:0]
+			for _, dir := range dirs {
+				globs = append(globs, filepath.Join(dir, pattern))
+			}
+		}
+		for _, glob := range globs {
+			matches, err := filepath.Glob(glob)
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range matches {
+				if _, ok := seen[match]; ok {
+					continue
+				}
+				seen[match] = struct{}{}
+				filenames = append(filenames, match)
+			}
+		}
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// Match checks if the file matches one of the glob patterns in the directories, see Find
+func Match(dirs, patterns []string, filename string) bool {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if filepath.IsAbs(pattern) {
+			if ok, _ := filepath.Match(pattern, filename); ok {
+				return true
+			}
+			continue
+		}
+		for _, dir := range dirs {
+			if ok, _ := filepath.Match(filepath.Join(dir, pattern), filename); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Load reads the fragment files, YAML or JSON, in the given order. The problems found in them are returned as
+// diagnostics, located in the files.
+func Load(filenames ...string) ([]*Fragment, diagnostic.List) {
+	var fragments []*Fragment
+	var diagnostics diagnostic.List
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			diagnostics = append(diagnostics, diagnostic.Diagnostic{
+				Severity: diagnostic.Error,
+				Filename: filename,
+				Message:  "the fragment file can't be read: " + err.Error(),
+			})
+			continue
+		}
+		fragment, found := Parse(filename, src)
+		diagnostics = append(diagnostics, found...)
+		if fragment != nil {
+			fragments = append(fragments, fragment)
+		}
+	}
+	return fragments, diagnostics
+}
+
+// Parse parses the content of a fragment file, it returns nil if the content isn't a valid YAML or JSON document
+func Parse(filename string, src []byte) (*Fragment, diagnostic.List) {
+	p := &parser{fragment: &Fragment{Filename: filename}}
+	var root yaml.Node
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		p.report(nil, diagnostic.Error, "", "the fragment file can't be parsed: %s", err)
+		return nil, p.diagnostics
+	}
+	if len(root.Content) == 0 {
+		// empty file
+		return p.fragment, nil
+	}
+
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		p.report(doc, diagnostic.Error, "", "the fragment file must be a mapping of %s and %s", nameKey, errorsKey)
+		return nil, p.diagnostics
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		switch key.Value {
+		case nameKey:
+			if p.scalar(key.Value, value) {
+				p.fragment.Name = value.Value
+			}
+		case errorsKey:
+			if !p.mapping(key.Value, value) {
+				continue
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				p.error(value.Content[j], value.Content[j+1])
+			}
+		default:
+			p.report(key, diagnostic.Warning, fmt.Sprintf("the supported keys are: %s, %s", nameKey, errorsKey),
+				"unknown key %q, the key is ignored", key.Value)
+		}
+	}
+	return p.fragment, p.diagnostics
+}
+
+// parser parses the nodes of a fragment file
+type parser struct {
+	fragment    *Fragment
+	diagnostics diagnostic.List
+}
+
+func (p *parser) error(key, value *yaml.Node) {
+	if !p.mapping("error "+key.Value, value) {
+		return
+	}
+	definition := &Error{Code: key.Value, Line: key.Line, Column: key.Column}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		field, node := value.Content[i], value.Content[i+1]
+		switch field.Value {
+		case codeKey:
+			if p.scalar(codeKey, node) && node.Value != definition.Code {
+				p.report(node, diagnostic.Warning, "", "the code %q of error %q doesn't match its key, the code is ignored", node.Value, definition.Code)
+			}
+		case "title", "short", "long":
+			p.field(definition, field.Value, node)
+		case metadataKey:
+			if !p.mapping(metadataKey, node) {
+				continue
+			}
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				p.field(definition, metadataKey+"."+node.Content[j].Value, node.Content[j+1])
+			}
+		case solutionsKey:
+			if !p.mapping(solutionsKey, node) {
+				continue
+			}
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				p.solution(definition, node.Content[j], node.Content[j+1])
+			}
+		default:
+			p.report(field, diagnostic.Warning, "the supported keys are: code, title, short, long, metadata, solutions",
+				"unknown key %q in error %q, the key is ignored", field.Value, definition.Code)
+		}
+	}
+	p.fragment.Errors = append(p.fragment.Errors, definition)
+}
+
+func (p *parser) solution(definition *Error, key, value *yaml.Node) {
+	if !p.mapping("solution "+key.Value, value) {
+		return
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		field, node := value.Content[i], value.Content[i+1]
+		switch field.Value {
+		case codeKey:
+			if p.scalar(codeKey, node) && node.Value != key.Value {
+				p.report(node, diagnostic.Warning, "", "the code %q of solution %q doesn't match its key, the code is ignored", node.Value, key.Value)
+			}
+		case "title", "short", "long":
+			p.field(definition, solutionsKey+"."+key.Value+"."+field.Value, node)
+		default:
+			p.report(field, diagnostic.Warning, "the supported keys are: code, title, short, long",
+				"unknown key %q in solution %q, the key is ignored", field.Value, key.Value)
+		}
+	}
+}
+
+func (p *parser) field(definition *Error, path string, node *yaml.Node) {
+	if !p.scalar(path, node) {
+		return
+	}
+	// the block scalars end with a line break
+	value := strings.TrimRight(node.Value, "\n")
+	definition.Fields = append(definition.Fields, Field{Path: path, Value: value, Line: node.Line, Column: node.Column})
+}
+
+func (p *parser) scalar(key string, node *yaml.Node) bool {
+	if node.Kind == yaml.ScalarNode {
+		return true
+	}
+	p.report(node, diagnostic.Error, "", "%s must be a string, the value is ignored", key)
+	return false
+}
+
+func (p *parser) mapping(key string, node *yaml.Node) bool {
+	if node.Kind == yaml.MappingNode {
+		return true
+	}
+	p.report(node, diagnostic.Error, "", "%s must be a mapping, the value is ignored", key)
+	return false
+}
+
+func (p *parser) report(node *yaml.Node, severity diagnostic.Severity, hint, format string, args ...any) {
+	d := diagnostic.Diagnostic{
+		Severity: severity,
+		Filename: p.fragment.Filename,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	}
+	if node != nil {
+		d.Line, d.Column = node.Line, node.Column
+	}
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+// Merge merges the fragments into the error definitions of the applications, by code. The fields of the fragments take
+// precedence over the annotations, the annotation values they replace are reported. The fragments are applied in order,
+// a field already set by a previous fragment keeps its first value, a different value is reported as a conflict.
+// The errors which aren't defined by the annotations are reported and skipped.
+// The location of the fragment supplying each field is recorded in the meta.sources of the error, sourcePath returns
+// the path recorded for a fragment file.
+func Merge(specs map[string]any, fragments []*Fragment, sourcePath func(filename string) string) diagnostic.List {
+	var diagnostics diagnostic.List
+	report := func(filename string, line, column int, severity diagnostic.Severity, hint, format string, args ...any) {
+		diagnostics = append(diagnostics, diagnostic.Diagnostic{
+			Severity: severity,
+			Filename: filename,
+			Line:     line,
+			Column:   column,
+			Message:  fmt.Sprintf(format, args...),
+			Hint:     hint,
+		})
+	}
+
+	type source struct {
+		filename string
+		field    Field
+	}
+	applied := map[string]source{}
+	for _, fragment := range fragments {
+		for _, e := range fragment.Errors {
+			spec, err := lookup(specs, fragment.Name, e.Code)
+			if err != "" {
+				report(fragment.Filename, e.Line, e.Column, diagnostic.Warning,
+					"the fragments complete the errors defined by the annotations", "%s, the error is skipped", err)
+				continue
+			}
+
+			definition := clone(spec.ErrorsDefinitions[e.Code])
+			for _, field := range e.Fields {
+				key := spec.Name + "\x00" + e.Code + "\x00" + field.Path
+				if previous, ok := applied[key]; ok {
+					if previous.field.Value != field.Value {
+						report(fragment.Filename, field.Line, field.Column, diagnostic.Error,
+							"define each field of an error in a single fragment file",
+							"%s of error %q is already defined by %s:%d, the value is ignored",
+							field.Path, e.Code, previous.filename, previous.field.Line)
+					}
+					continue
+				}
+				if current := get(definition, field.Path); current != "" && current != field.Value {
+					report(fragment.Filename, field.Line, field.Column, diagnostic.Warning,
+						"the fragments take precedence over the annotations, remove the value from one of them",
+						"%s of error %q overrides the value of the annotation at %s", field.Path, e.Code, annotationPosition(definition))
+				}
+				set(&definition, field.Path, field.Value)
+				definition.Meta.Sources[field.Path] = api.ErrorMetaLoc{
+					Path:   sourcePath(fragment.Filename),
+					Line:   intPtr(field.Line),
+					Column: intPtr(field.Column),
+				}
+				applied[key] = source{filename: fragment.Filename, field: field}
+			}
+			for _, code := range solutionCodes(e) {
+				if definition.Solutions[code].Short == "" {
+					report(fragment.Filename, e.Line, e.Column, diagnostic.Warning, "",
+						"solution %q of error %q has no short description", code, e.Code)
+				}
+			}
+			if len(definition.Meta.Sources) == 0 {
+				definition.Meta.Sources = nil
+			}
+			spec.ErrorsDefinitions[e.Code] = definition
+		}
+	}
+	return diagnostics
+}
+
+// lookup returns the application defining the error, only the named application is searched if name isn't empty.
+// It returns the reason the error can't be merged otherwise.
+func lookup(specs map[string]any, name, code string) (*api.Manifest, string) {
+	if name != "" {
+		spec, ok := specs[name].(*api.Manifest)
+		if !ok {
+			return nil, fmt.Sprintf("the application %q isn't defined", name)
+		}
+		if _, ok := spec.ErrorsDefinitions[code]; !ok {
+			return nil, fmt.Sprintf("error %q isn't defined by the annotations of the application %q", code, name)
+		}
+		return spec, ""
+	}
+
+	var found []string
+	for specName, s := range specs {
+		if spec, ok := s.(*api.Manifest); ok {
+			if _, ok := spec.ErrorsDefinitions[code]; ok {
+				found = append(found, specName)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Sprintf("error %q isn't defined by the annotations", code)
+	case 1:
+		return specs[found[0]].(*api.Manifest), ""
+	}
+	sort.Strings(found)
+	return nil, fmt.Sprintf("error %q is defined by the applications %s, name the application of the fragment", code, strings.Join(found, ", "))
+}
+
+// clone copies the error definition, so it can be modified without affecting the annotations it was parsed from
+func clone(definition api.Error) api.Error {
+	meta := api.ErrorMeta{}
+	if definition.Meta != nil {
+		meta = *definition.Meta
+	}
+	sources := make(map[string]api.ErrorMetaLoc, len(meta.Sources))
+	for k, v := range meta.Sources {
+		sources[k] = v
+	}
+	meta.Sources = sources
+	definition.Meta = &meta
+
+	if definition.Metadata != nil {
+		metadata := make(api.ErrorMetadata, len(definition.Metadata))
+		for k, v := range definition.Metadata {
+			metadata[k] = v
+		}
+		definition.Metadata = metadata
+	}
+	solutions := make(api.Solutions, len(definition.Solutions))
+	for k, v := range definition.Solutions {
+		solutions[k] = v
+	}
+	definition.Solutions = solutions
+	return definition
+}
+
+// get returns the value of the field of the error definition, an empty string if it isn't set
+func get(definition api.Error, path string) string {
+	switch {
+	case path == "title":
+		return definition.Title
+	case path == "short":
+		return definition.Short
+	case path == "long":
+		return stringValue(definition.Long)
+	case strings.HasPrefix(path, metadataKey+"."):
+		return definition.Metadata[strings.TrimPrefix(path, metadataKey+".")]
+	case strings.HasPrefix(path, solutionsKey+"."):
+		code, field := solutionField(path)
+		solution, ok := definition.Solutions[code]
+		if !ok {
+			return ""
+		}
+		switch field {
+		case "title":
+			return stringValue(solution.Title)
+		case "short":
+			return solution.Short
+		case "long":
+			return stringValue(solution.Long)
+		}
+	}
+	return ""
+}
+
+// set sets the field of the error definition, the metadata and solutions maps must be owned by the definition
+func set(definition *api.Error, path, value string) {
+	switch {
+	case path == "title":
+		definition.Title = value
+	case path == "short":
+		definition.Short = value
+	case path == "long":
+		definition.Long = &value
+	case strings.HasPrefix(path, metadataKey+"."):
+		if definition.Metadata == nil {
+			definition.Metadata = api.ErrorMetadata{}
+		}
+		definition.Metadata[strings.TrimPrefix(path, metadataKey+".")] = value
+	case strings.HasPrefix(path, solutionsKey+"."):
+		code, field := solutionField(path)
+		solution, ok := definition.Solutions[code]
+		if !ok {
+			solution = api.Solution{Code: code}
+		}
+		switch field {
+		case "title":
+			solution.Title = &value
+		case "short":
+			solution.Short = value
+		case "long":
+			solution.Long = &value
+		}
+		definition.Solutions[code] = solution
+	}
+}
+
+// solutionCodes returns the codes of the solutions the fragment error sets fields of
+func solutionCodes(e *Error) []string {
+	seen := map[string]struct{}{}
+	var codes []string
+	for _, field := range e.Fields {
+		if !strings.HasPrefix(field.Path, solutionsKey+".") {
+			continue
+		}
+		code, _ := solutionField(field.Path)
+		if _, ok := seen[code]; !ok {
+			seen[code] = struct{}{}
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// solutionField splits the path of a solution field, solutions.<code>.<field>, into the solution code and field name
+func solutionField(path string) (string, string) {
+	path = strings.TrimPrefix(path, solutionsKey+".")
+	i := strings.LastIndex(path, ".")
+	return path[:i], path[i+1:]
+}
+
+// annotationPosition returns the position of the annotation defining the error, i.e: store/store.go:12
+func annotationPosition(definition api.Error) string {
+	if definition.Meta == nil || definition.Meta.Loc == nil {
+		return "unknown location"
+	}
+	if definition.Meta.Loc.Line == nil {
+		return definition.Meta.Loc.Path
+	}
+	return fmt.Sprintf("%s:%d", definition.Meta.Loc.Path, *definition.Meta.Loc.Line)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
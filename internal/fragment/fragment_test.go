@@ -0,0 +1,193 @@
+package fragment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func messages(diagnostics diagnostic.List) []string {
+	var found []string
+	for _, d := range diagnostics {
+		found = append(found, fmt.Sprintf("%s:%d:%d: %s", filepath.Base(d.Filename), d.Line, d.Column, d.Message))
+	}
+	return found
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully parse the fields of a YAML fragment", func(t *testing.T) {
+		t.Parallel()
+		fragment, diagnostics := Parse("errors.yaml", []byte(`name: app
+errors_definitions:
+  not_found:
+    code: not_found
+    long: |
+      The resource wasn't found.
+    metadata:
+      owner: team-storage
+    solutions:
+      check_name:
+        short: Check the name.
+`))
+		assert.Empty(t, diagnostics)
+		require.NotNil(t, fragment)
+		assert.Equal(t, "app", fragment.Name)
+		require.Len(t, fragment.Errors, 1)
+		assert.Equal(t, &Error{Code: "not_found", Line: 3, Column: 3, Fields: []Field{
+			{Path: "long", Value: "The resource wasn't found.", Line: 5, Column: 11},
+			{Path: "metadata.owner", Value: "team-storage", Line: 8, Column: 14},
+			{Path: "solutions.check_name.short", Value: "Check the name.", Line: 11, Column: 16},
+		}}, fragment.Errors[0])
+	})
+	t.Run("Successfully parse a JSON fragment", func(t *testing.T) {
+		t.Parallel()
+		fragment, diagnostics := Parse("errors.json", []byte(`{"errors_definitions": {"not_found": {"title": "Not Found"}}}`))
+		assert.Empty(t, diagnostics)
+		require.NotNil(t, fragment)
+		require.Len(t, fragment.Errors, 1)
+		assert.Equal(t, []Field{{Path: "title", Value: "Not Found", Line: 1, Column: 48}}, fragment.Errors[0].Fields)
+	})
+	t.Run("Successfully report the unknown keys and invalid values", func(t *testing.T) {
+		t.Parallel()
+		fragment, diagnostics := Parse("errors.yaml", []byte(`version: v1
+errors_definitions:
+  not_found:
+    code: missing
+    titel: Not Found
+    short: [a, b]
+`))
+		require.NotNil(t, fragment)
+		assert.Equal(t, []string{
+			`errors.yaml:1:1: unknown key "version", the key is ignored`,
+			`errors.yaml:4:11: the code "missing" of error "not_found" doesn't match its key, the code is ignored`,
+			`errors.yaml:5:5: unknown key "titel" in error "not_found", the key is ignored`,
+			`errors.yaml:6:12: short must be a string, the value is ignored`,
+		}, messages(diagnostics))
+	})
+	t.Run("Fail to parse an invalid document", func(t *testing.T) {
+		t.Parallel()
+		fragment, diagnostics := Parse("errors.yaml", []byte("- not_found\n"))
+		assert.Nil(t, fragment)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, diagnostic.Error, diagnostics[0].Severity)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	line := 12
+	specs := func() map[string]any {
+		long := "The annotation description."
+		return map[string]any{
+			"app": &api.Manifest{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{
+				"not_found": {
+					Code:      "not_found",
+					Title:     "Not Found",
+					Short:     "The resource wasn't found.",
+					Long:      &long,
+					Solutions: api.Solutions{"retry": {Code: "retry", Short: "Retry."}},
+					Meta:      &api.ErrorMeta{Loc: &api.ErrorMetaLoc{Path: "store/store.go", Line: &line}},
+				},
+			}},
+			"other": &api.Manifest{Name: "other", ErrorsDefinitions: api.ErrorDefinitions{
+				"shared": {Code: "shared", Title: "Shared", Short: "Shared."},
+			}},
+			"third": &api.Manifest{Name: "third", ErrorsDefinitions: api.ErrorDefinitions{
+				"shared": {Code: "shared", Title: "Shared", Short: "Shared."},
+			}},
+		}
+	}
+	parse := func(t *testing.T, filename, content string) *Fragment {
+		fragment, diagnostics := Parse(filename, []byte(content))
+		require.Empty(t, diagnostics)
+		return fragment
+	}
+
+	t.Run("Successfully merge the fields of the fragments by code", func(t *testing.T) {
+		t.Parallel()
+		merged := specs()
+		original := *merged["app"].(*api.Manifest).ErrorsDefinitions["not_found"].Long
+		diagnostics := Merge(merged, []*Fragment{
+			parse(t, "a.yaml", "errors_definitions:\n  not_found:\n    solutions:\n      check_name:\n        short: Check the name.\n"),
+			parse(t, "b.yaml", "errors_definitions:\n  not_found:\n    metadata:\n      owner: team-storage\n"),
+		}, func(filename string) string { return "errors.d/" + filename })
+		assert.Empty(t, diagnostics)
+
+		definition := merged["app"].(*api.Manifest).ErrorsDefinitions["not_found"]
+		assert.Equal(t, original, *definition.Long)
+		assert.Equal(t, api.Solutions{
+			"retry":      {Code: "retry", Short: "Retry."},
+			"check_name": {Code: "check_name", Short: "Check the name."},
+		}, definition.Solutions)
+		assert.Equal(t, api.ErrorMetadata{"owner": "team-storage"}, definition.Metadata)
+
+		require.NotNil(t, definition.Meta)
+		assert.Equal(t, "store/store.go", definition.Meta.Loc.Path)
+		require.Len(t, definition.Meta.Sources, 2)
+		assert.Equal(t, "errors.d/a.yaml", definition.Meta.Sources["solutions.check_name.short"].Path)
+		assert.Equal(t, 5, *definition.Meta.Sources["solutions.check_name.short"].Line)
+		assert.Equal(t, "errors.d/b.yaml", definition.Meta.Sources["metadata.owner"].Path)
+	})
+	t.Run("Successfully report the overridden annotations and conflicting fragments", func(t *testing.T) {
+		t.Parallel()
+		merged := specs()
+		diagnostics := Merge(merged, []*Fragment{
+			parse(t, "a.yaml", "errors_definitions:\n  not_found:\n    long: The fragment description.\n"),
+			parse(t, "b.yaml", "errors_definitions:\n  not_found:\n    long: Another description.\n"),
+		}, func(filename string) string { return filename })
+		assert.Equal(t, []string{
+			`a.yaml:3:11: long of error "not_found" overrides the value of the annotation at store/store.go:12`,
+			`b.yaml:3:11: long of error "not_found" is already defined by a.yaml:3, the value is ignored`,
+		}, messages(diagnostics))
+		assert.Equal(t, diagnostic.Warning, diagnostics[0].Severity)
+		assert.Equal(t, diagnostic.Error, diagnostics[1].Severity)
+		assert.Equal(t, "The fragment description.", *merged["app"].(*api.Manifest).ErrorsDefinitions["not_found"].Long)
+	})
+	t.Run("Successfully skip the errors without annotations", func(t *testing.T) {
+		t.Parallel()
+		merged := specs()
+		diagnostics := Merge(merged, []*Fragment{
+			parse(t, "a.yaml", "errors_definitions:\n  unknown:\n    title: Unknown\n  shared:\n    title: Shared\n"),
+			parse(t, "b.yaml", "name: missing\nerrors_definitions:\n  not_found:\n    title: Missing\n"),
+			parse(t, "c.yaml", "name: other\nerrors_definitions:\n  shared:\n    long: Shared.\n"),
+		}, func(filename string) string { return filename })
+		assert.Equal(t, []string{
+			`a.yaml:2:3: error "unknown" isn't defined by the annotations, the error is skipped`,
+			`a.yaml:4:3: error "shared" is defined by the applications other, third, name the application of the fragment, the error is skipped`,
+			`b.yaml:3:3: the application "missing" isn't defined, the error is skipped`,
+		}, messages(diagnostics))
+		assert.NotNil(t, merged["other"].(*api.Manifest).ErrorsDefinitions["shared"].Long)
+		assert.Nil(t, merged["third"].(*api.Manifest).ErrorsDefinitions["shared"].Long)
+	})
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, name := range []string{"errors.d/b.yaml", "errors.d/a.json", "errors.d/notes.txt", "sub/errors.d/c.yml"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	found, err := Find([]string{root, filepath.Join(root, "sub")}, DefaultPatterns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "errors.d", "a.json"),
+		filepath.Join(root, "errors.d", "b.yaml"),
+		filepath.Join(root, "sub", "errors.d", "c.yml"),
+	}, found)
+	assert.True(t, Match([]string{root}, DefaultPatterns, filepath.Join(root, "errors.d", "b.yaml")))
+	assert.False(t, Match([]string{root}, DefaultPatterns, filepath.Join(root, "errors.d", "notes.txt")))
+	assert.False(t, Match([]string{root}, nil, filepath.Join(root, "errors.d", "b.yaml")))
+}
@@ -0,0 +1,2 @@
+// Package migrate rewrites the legacy annotations, i.e: @aloe, into the current @fyi grammar
+package migrate
@@ -0,0 +1,335 @@
+package migrate
+
+import (
+	"context"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/diff"
+	"github.com/tfadeyi/errors/internal/logging"
+)
+
+type (
+	// Migrator rewrites the legacy annotations found in the go source files into the current grammar
+	Migrator struct {
+		logger   *logging.Logger
+		prefixes []string
+		dryRun   bool
+	}
+
+	// Options contains the configuration options available to the Migrator
+	Options struct {
+		Logger *logging.Logger
+		// Prefixes are the legacy annotation prefixes to rewrite, i.e: @aloe
+		Prefixes []string
+		// DryRun computes the changes without writing them to the files
+		DryRun bool
+	}
+
+	// Result contains the changes made, or to be made in dry-run mode, to a file
+	Result struct {
+		File string
+		// Annotations is the number of annotations rewritten in the file
+		Annotations int
+		// Diff is the unified diff of the changes
+		Diff string
+	}
+)
+
+const (
+	// Prefix is the prefix of the annotations in the current grammar
+	Prefix = "@fyi"
+)
+
+var (
+	// DefaultLegacyPrefixes are the annotation prefixes used by the previous versions of the tool
+	DefaultLegacyPrefixes = []string{"@aloe"}
+
+	// keyAliases maps the legacy keys to the keys of the current grammar
+	keyAliases = map[string]string{
+		"summary": "short",
+		"details": "long",
+		"url":     "base_url",
+	}
+	// errorKeys are the unscoped legacy keys that belonged to an error definition
+	errorKeys = map[string]bool{
+		"code":  true,
+		"short": true,
+		"long":  true,
+	}
+)
+
+// New creates a new instance of the migrator
+func New(opts *Options) *Migrator {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+	prefixes := opts.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultLegacyPrefixes
+	}
+	return &Migrator{
+		logger:   opts.Logger,
+		prefixes: prefixes,
+		dryRun:   opts.DryRun,
+	}
+}
+
+// Migrate rewrites the legacy annotations of the go files in the given directories and their subdirectories.
+// Hidden, vendor and testdata directories are skipped. The results are sorted by filename.
+func (m *Migrator) Migrate(ctx context.Context, dirs ...string) ([]Result, error) {
+	var files []string
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) == ".go" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	var results []Result
+	for _, file := range files {
+		// handle signals with context
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("termination signal was received, terminating process...")
+		default:
+		}
+
+		result, err := m.migrateFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if result.Annotations > 0 {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (m *Migrator) migrateFile(filename string) (Result, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return Result{}, err
+	}
+
+	updated, count, err := RewriteSource(filename, src, m.prefixes...)
+	if err != nil {
+		return Result{}, err
+	}
+	if count == 0 {
+		return Result{File: filename}, nil
+	}
+
+	result := Result{
+		File:        filename,
+		Annotations: count,
+		Diff:        diff.Unified("a/"+filepath.ToSlash(filename), "b/"+filepath.ToSlash(filename), src, updated),
+	}
+	if m.dryRun {
+		return result, nil
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(filename, updated, info.Mode().Perm()); err != nil {
+		return Result{}, errors.Annotatef(err, "could not write the migrated file %q", filename)
+	}
+	return result, nil
+}
+
+// RewriteSource rewrites the legacy annotations in the line and block comments of the go source, leaving the rest of
+// the source untouched. It returns the updated source and the number of rewritten annotations.
+func RewriteSource(filename string, src []byte, prefixes ...string) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, filename, src, goparser.ParseComments|goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type replacement struct {
+		start, end int
+		text       string
+	}
+	var replacements []replacement
+	count := 0
+
+	for _, group := range file.Comments {
+		// the lines of the comments of the group, a block comment spans several lines, the same way the parser
+		// reads them from the text of the group
+		var lines []string
+		spans := make([]int, len(group.List))
+		for i, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "//") {
+				lines = append(lines, strings.TrimPrefix(comment.Text, "//"))
+				spans[i] = 1
+				continue
+			}
+			body := strings.Split(strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/"), "\n")
+			lines = append(lines, body...)
+			spans[i] = len(body)
+		}
+
+		rewritten, n := rewriteLines(lines, prefixes)
+		if n == 0 {
+			continue
+		}
+		count += n
+		first := 0
+		for i, comment := range group.List {
+			last := first + spans[i]
+			changed := false
+			for j := first; j < last; j++ {
+				changed = changed || rewritten[j] != lines[j]
+			}
+			text := "//" + rewritten[first]
+			if !strings.HasPrefix(comment.Text, "//") {
+				text = "/*" + strings.Join(rewritten[first:last], "\n") + "*/"
+			}
+			first = last
+			if !changed {
+				continue
+			}
+			replacements = append(replacements, replacement{
+				start: fset.Position(comment.Pos()).Offset,
+				end:   fset.Position(comment.End()).Offset,
+				text:  text,
+			})
+		}
+	}
+
+	if count == 0 {
+		return src, 0, nil
+	}
+
+	updated := make([]byte, 0, len(src))
+	last := 0
+	for _, r := range replacements {
+		updated = append(updated, src[last:r.start]...)
+		updated = append(updated, r.text...)
+		last = r.end
+	}
+	updated = append(updated, src[last:]...)
+	return updated, count, nil
+}
+
+// RewriteComment rewrites the legacy annotations in the text of a comment group, i.e: ast.CommentGroup.Text().
+// It returns true if any annotation was rewritten.
+func RewriteComment(text string, prefixes ...string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	rewritten, n := rewriteLines(lines, prefixes)
+	if n == 0 {
+		return text, false
+	}
+	return strings.Join(rewritten, "\n"), true
+}
+
+type annotation struct {
+	indent, scope, key, separator, value string
+}
+
+// parseLine splits a legacy annotation line, i.e: " @aloe.error code my_code", into its parts.
+func parseLine(line string, prefixes []string) (annotation, bool) {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+	indent := line[:len(line)-len(trimmed)]
+
+	for _, prefix := range prefixes {
+		if prefix == "" || prefix == Prefix || !strings.HasPrefix(trimmed, prefix) {
+			continue
+		}
+		rest := trimmed[len(prefix):]
+		if rest != "" && rest[0] != '.' && !unicode.IsSpace(rune(rest[0])) {
+			// i.e: @aloeapp is not an annotation
+			continue
+		}
+
+		var scope string
+		if strings.HasPrefix(rest, ".") {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			scope, rest = rest[:end], rest[end:]
+		}
+
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		key, value := rest[:end], rest[end:]
+		valueStart := strings.TrimLeftFunc(value, unicode.IsSpace)
+		separator := value[:len(value)-len(valueStart)]
+		if separator == "" && valueStart != "" {
+			separator = " "
+		}
+
+		return annotation{indent: indent, scope: scope, key: key, separator: separator, value: valueStart}, true
+	}
+	return annotation{}, false
+}
+
+// rewriteLines rewrites the legacy annotations of a comment group. Unscoped legacy annotations are assigned to the
+// error scope if the comment group defines an error code, to the application scope otherwise.
+func rewriteLines(lines []string, prefixes []string) ([]string, int) {
+	annotations := make([]*annotation, len(lines))
+	errorGroup := false
+	for i, line := range lines {
+		a, ok := parseLine(line, prefixes)
+		if !ok {
+			continue
+		}
+		if alias, ok := keyAliases[strings.ToLower(a.key)]; ok {
+			a.key = alias
+		}
+		if a.scope == "" && strings.ToLower(a.key) == "code" {
+			errorGroup = true
+		}
+		annotations[i] = &a
+	}
+
+	result := make([]string, len(lines))
+	count := 0
+	for i, line := range lines {
+		a := annotations[i]
+		if a == nil {
+			result[i] = line
+			continue
+		}
+		scope := a.scope
+		if scope == "" {
+			key := strings.ToLower(a.key)
+			if errorKeys[key] || (errorGroup && key == "title") {
+				scope = ".error"
+			}
+		}
+		result[i] = a.indent + Prefix + scope + " " + a.key + a.separator + a.value
+		count++
+	}
+	return result, count
+}
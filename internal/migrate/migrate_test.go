@@ -0,0 +1,109 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully rewrite an unscoped legacy error definition", func(t *testing.T) {
+		text, ok := RewriteComment(`@aloe code invalid_log_level
+@aloe title Invalid Log-Level Argument
+@aloe summary The log level is not supported.
+@aloe details The log level is not currently supported by the tool.
+The following are supported: none, debug, info(default), warn.`, "@aloe")
+		require.True(t, ok)
+		assert.Equal(t, `@fyi.error code invalid_log_level
+@fyi.error title Invalid Log-Level Argument
+@fyi.error short The log level is not supported.
+@fyi.error long The log level is not currently supported by the tool.
+The following are supported: none, debug, info(default), warn.`, text)
+	})
+	t.Run("Successfully rewrite legacy application information", func(t *testing.T) {
+		text, ok := RewriteComment(`@aloe name cli
+@aloe title CLI
+@aloe url https://tfadeyi.github.io
+@aloe version v0.1.0`, "@aloe")
+		require.True(t, ok)
+		assert.Equal(t, `@fyi name cli
+@fyi title CLI
+@fyi base_url https://tfadeyi.github.io
+@fyi version v0.1.0`, text)
+	})
+	t.Run("Successfully keep the scope of scoped legacy annotations", func(t *testing.T) {
+		text, ok := RewriteComment("@aloe.error.solution code try_again", "@aloe")
+		require.True(t, ok)
+		assert.Equal(t, "@fyi.error.solution code try_again", text)
+	})
+	t.Run("Successfully leave the current grammar and unrelated comments untouched", func(t *testing.T) {
+		_, ok := RewriteComment("@fyi.error code my_code\nsee @aloe docs", "@aloe")
+		assert.False(t, ok)
+		_, ok = RewriteComment("@aloebar code my_code", "@aloe")
+		assert.False(t, ok)
+	})
+}
+
+func TestRewriteSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully rewrite only the legacy annotation comments", func(t *testing.T) {
+		src := []byte(`package options
+
+func Complete() error {
+	// @aloe code invalid_log_level
+	// @aloe short The log level is not supported.
+	return nil // see @aloe docs
+}
+`)
+		updated, count, err := RewriteSource("options.go", src, "@aloe")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, `package options
+
+func Complete() error {
+	// @fyi.error code invalid_log_level
+	// @fyi.error short The log level is not supported.
+	return nil // see @aloe docs
+}
+`, string(updated))
+	})
+	t.Run("Successfully rewrite the legacy annotations of the block comments", func(t *testing.T) {
+		src := []byte(`package options
+
+/*
+@aloe code invalid_log_level
+@aloe summary The log level is not supported.
+The following are supported: none, debug.
+*/
+var errInvalidLogLevel error
+
+/* @aloe name cli */
+// @aloe url https://tfadeyi.github.io
+func Complete() error {
+	return nil /* see the docs */
+}
+`)
+		updated, count, err := RewriteSource("options.go", src, "@aloe")
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+		assert.Equal(t, `package options
+
+/*
+@fyi.error code invalid_log_level
+@fyi.error short The log level is not supported.
+The following are supported: none, debug.
+*/
+var errInvalidLogLevel error
+
+/* @fyi name cli */
+// @fyi base_url https://tfadeyi.github.io
+func Complete() error {
+	return nil /* see the docs */
+}
+`, string(updated))
+	})
+}
@@ -0,0 +1,2 @@
+// Package module locates the go module containing the parsed source code
+package module
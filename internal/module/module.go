@@ -0,0 +1,172 @@
+package module
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	// GoModFilename is the name of the file defining a go module
+	GoModFilename = "go.mod"
+	// GoWorkFilename is the name of the file defining a go workspace
+	GoWorkFilename = "go.work"
+)
+
+var (
+	ErrNoModule    = errors.New("no go.mod file was found in the directory or any of its parents")
+	ErrNoWorkspace = errors.New("no go.work file was found in the directory or any of its parents")
+)
+
+// FindRoot returns the root directory of the go module containing the given directory,
+// i.e: the closest parent directory containing a go.mod file.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if info, err := os.Stat(filepath.Join(dir, GoModFilename)); err == nil && !info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoModule
+		}
+		dir = parent
+	}
+}
+
+// Path returns the module path declared in the go.mod file of the given module root directory
+func Path(root string) (string, error) {
+	body, err := os.ReadFile(filepath.Join(root, GoModFilename))
+	if err != nil {
+		return "", err
+	}
+	path := modfile.ModulePath(body)
+	if path == "" {
+		return "", errors.Errorf("no module path was found in %q", filepath.Join(root, GoModFilename))
+	}
+	return path, nil
+}
+
+// FindWorkspace returns the path of the go.work file of the workspace containing the given directory,
+// i.e: the closest go.work file in the directory or its parents. The GOWORK environment variable takes precedence,
+// GOWORK=off disables the workspace.
+func FindWorkspace(dir string) (string, error) {
+	switch env := os.Getenv("GOWORK"); env {
+	case "":
+	case "off":
+		return "", ErrNoWorkspace
+	default:
+		return env, nil
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, GoWorkFilename)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoWorkspace
+		}
+		dir = parent
+	}
+}
+
+// WorkspaceModules returns the absolute root directories of the modules used by the go.work file
+func WorkspaceModules(workFile string) ([]string, error) {
+	body, err := os.ReadFile(workFile)
+	if err != nil {
+		return nil, err
+	}
+	work, err := modfile.ParseWork(workFile, body, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to parse the workspace file %q", workFile)
+	}
+
+	base := filepath.Dir(workFile)
+	var roots []string
+	for _, use := range work.Use {
+		root := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(base, root)
+		}
+		roots = append(roots, filepath.Clean(root))
+	}
+	return roots, nil
+}
+
+// Resolver resolves the import paths of the packages in go modules, caching the modules it finds
+type Resolver struct {
+	// roots maps the directories to the root directory of their module
+	roots map[string]string
+	// paths maps the module root directories to their module path
+	paths map[string]string
+}
+
+// NewResolver creates a new instance of the import path resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		roots: map[string]string{},
+		paths: map[string]string{},
+	}
+}
+
+// Root returns the root directory of the go module containing the given directory, see FindRoot.
+func (r *Resolver) Root(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	root, ok := r.roots[dir]
+	if !ok {
+		root, err = FindRoot(dir)
+		if err != nil {
+			return "", err
+		}
+		r.roots[dir] = root
+	}
+	return root, nil
+}
+
+// ImportPath returns the import path of the package in the given directory, i.e: github.com/tfadeyi/errors/cmd/app.
+// ErrNoModule is returned if the directory isn't part of a go module.
+func (r *Resolver) ImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := r.Root(dir)
+	if err != nil {
+		return "", err
+	}
+
+	modulePath, ok := r.paths[root]
+	if !ok {
+		modulePath, err = Path(root)
+		if err != nil {
+			return "", err
+		}
+		r.paths[root] = modulePath
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return modulePath, nil
+	}
+	return path.Join(modulePath, filepath.ToSlash(rel)), nil
+}
@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/diff"
+)
+
+type (
+	// Renderer is implemented by the content generators able to render their files in memory
+	Renderer interface {
+		// Render returns the generated files keyed by their path, without writing them
+		Render(ctx context.Context, specs map[string]any) (map[string][]byte, error)
+		// Outputs returns the previously generated files found on disk, the ones not rendered anymore are deleted by Generate
+		Outputs() ([]string, error)
+	}
+
+	// StaleFile is a generated file on disk out of sync with the source code
+	StaleFile struct {
+		Path string
+		// Diff is the unified diff between the file on disk and the generated content
+		Diff string
+	}
+)
+
+// Check renders the specs in memory and compares the result with the files on disk, nothing is written.
+// It returns the stale files sorted by path: the outdated or missing files, and the files Generate would delete.
+func Check(ctx context.Context, renderer Renderer, specs map[string]any) ([]StaleFile, error) {
+	files, err := renderer.Render(ctx, specs)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := renderer.Outputs()
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []StaleFile
+	for path, body := range files {
+		current, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			stale = append(stale, StaleFile{Path: path, Diff: diff.Unified("", diffName("b/", path), nil, body)})
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if d := diff.Unified(diffName("a/", path), diffName("b/", path), current, body); d != "" {
+			stale = append(stale, StaleFile{Path: path, Diff: d})
+		}
+	}
+	for _, path := range outputs {
+		if _, ok := files[path]; ok {
+			continue
+		}
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, StaleFile{Path: path, Diff: diff.Unified(diffName("a/", path), "", current, nil)})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Path < stale[j].Path
+	})
+	return stale, nil
+}
+
+// diffName returns the name of the file in the diff headers, i.e: a/docs/index.md.
+// Paths are relative to the working directory when possible, paths outside of it are left absolute without prefix.
+func diffName(prefix, path string) string {
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	if filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	return prefix + filepath.ToSlash(path)
+}
@@ -0,0 +1,64 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRenderer struct {
+	files   map[string][]byte
+	outputs []string
+}
+
+func (f *fakeRenderer) Render(context.Context, map[string]any) (map[string][]byte, error) {
+	return f.files, nil
+}
+
+func (f *fakeRenderer) Outputs() ([]string, error) {
+	return f.outputs, nil
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	upToDate := filepath.Join(dir, "index.md")
+	outdated := filepath.Join(dir, "errors", "changed.md")
+	missing := filepath.Join(dir, "errors", "added.md")
+	deleted := filepath.Join(dir, "errors", "removed.md")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "errors"), 0755))
+	require.NoError(t, os.WriteFile(upToDate, []byte("index\n"), 0644))
+	require.NoError(t, os.WriteFile(outdated, []byte("before\n"), 0644))
+	require.NoError(t, os.WriteFile(deleted, []byte("removed\n"), 0644))
+
+	stale, err := Check(context.Background(), &fakeRenderer{
+		files: map[string][]byte{
+			upToDate: []byte("index\n"),
+			outdated: []byte("after\n"),
+			missing:  []byte("added\n"),
+		},
+		outputs: []string{upToDate, outdated, deleted},
+	}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, stale, 3)
+	assert.Equal(t, missing, stale[0].Path)
+	assert.Contains(t, stale[0].Diff, "--- /dev/null\n")
+	assert.Contains(t, stale[0].Diff, "+added\n")
+	assert.Equal(t, outdated, stale[1].Path)
+	assert.Contains(t, stale[1].Diff, "-before\n+after\n")
+	assert.Equal(t, deleted, stale[2].Path)
+	assert.Contains(t, stale[2].Diff, "+++ /dev/null\n")
+
+	// nothing is written to disk
+	_, err = os.Stat(missing)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(deleted)
+	assert.NoError(t, err)
+}
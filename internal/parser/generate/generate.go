@@ -6,7 +6,7 @@ import (
 )
 
 type (
-	// Target is an abstraction for content generators, i.e: yaml and markdown.
+	// Target is an abstraction for content generators, i.e: yaml, json, markdown and html.
 	Target interface {
 		// Generate transcribes the content from the specs to the writer
 		Generate(ctx context.Context, specs map[string]any) error
@@ -17,7 +17,7 @@ type (
 func IsSupportedOutputFormat(format string) bool {
 	format = strings.ToLower(strings.TrimSpace(format))
 	switch format {
-	case Yaml, Markdown:
+	case Yaml, JSON, Markdown, HTML:
 		return true
 	}
 	return false
@@ -25,5 +25,7 @@ func IsSupportedOutputFormat(format string) bool {
 
 const (
 	Yaml     = "yaml"
+	JSON     = "json"
 	Markdown = "markdown"
+	HTML     = "html"
 )
@@ -4,8 +4,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/pkg/api"
 )
 
 // Clean removes all the files
@@ -22,12 +26,60 @@ func Clean(files ...string) error {
 	return nil
 }
 
-// Write the files to the writer, the caller is in charge of closing the writer
+// Existing returns the given files that exist on disk
+func Existing(files ...string) ([]string, error) {
+	var found []string
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		_, err := os.Stat(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, file)
+	}
+	return found, nil
+}
+
+// ValidateFileName checks the application name or error code can be used as the name of a generated file or
+// directory, the names containing a path separator or referring to a parent directory would write outside the output
+func ValidateFileName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return errors.Errorf("%q can't be used as the name of a generated file", name)
+	}
+	return nil
+}
+
+// NamePlaceholder is replaced by the application name in the output path, i.e: errors-{name}.yaml, so each application
+// is rendered to its own file
+const NamePlaceholder = "{name}"
+
+// IsOutputPattern checks if the output path contains the NamePlaceholder
+func IsOutputPattern(output string) bool {
+	return strings.Contains(output, NamePlaceholder)
+}
+
+// ExpandOutput returns the output path of the application, the NamePlaceholder is replaced by its name
+func ExpandOutput(output, name string) string {
+	return filepath.Clean(strings.ReplaceAll(output, NamePlaceholder, name))
+}
+
+// Write the files to the writer sorted by path, the caller is in charge of closing the writer
 func Write(w io.Writer, files map[string][]byte) error {
-	for _, body := range files {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
 		var err error
 		// write to writer, this must be closed by the caller
-		_, err = w.Write(body)
+		_, err = w.Write(files[path])
 		if err != nil {
 			return err
 		}
@@ -41,14 +93,28 @@ func WriteToFile(files map[string][]byte) error {
 		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 			return err
 		}
-		w, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
-		if err != nil {
-			return err
-		}
-		_, err = w.Write(body)
-		if err != nil {
+		if err := os.WriteFile(path, body, 0644); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SourceURL expands the repository URL template with the location of the error, i.e:
+// https://github.com/org/repo/blob/main/{path}#L{line}. The {path}, {line} and {column} placeholders are replaced by
+// the module relative path, line and column of the error annotation. An empty string is returned if either the template
+// or the location is missing.
+func SourceURL(template string, definition api.Error) string {
+	if template == "" || definition.Meta == nil || definition.Meta.Loc == nil || definition.Meta.Loc.Path == "" {
+		return ""
+	}
+	loc := definition.Meta.Loc
+	line, column := "", ""
+	if loc.Line != nil {
+		line = strconv.Itoa(*loc.Line)
+	}
+	if loc.Column != nil {
+		column = strconv.Itoa(*loc.Column)
+	}
+	return strings.NewReplacer("{path}", loc.Path, "{line}", line, "{column}", column).Replace(template)
+}
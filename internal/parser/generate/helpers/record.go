@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// OutputsRecord is the file recording the files generated in its directory, keyed by the format and the output they
+// were generated for. Only the recorded files are removed once they aren't generated anymore, so the other files of the
+// directory are never touched.
+const OutputsRecord = ".errctl-outputs.json"
+
+// GenerateManifests writes the files of the manifest generators, their output is a file or an output pattern.
+// The files are written to the writer if there is no output, the files generated for an output pattern are recorded
+// and the files previously generated for it are removed once they aren't generated anymore, see WriteOutputs.
+func GenerateManifests(format string, w io.Writer, output string, files map[string][]byte) error {
+	if output == "" {
+		return Write(w, files)
+	}
+	if !IsOutputPattern(output) {
+		return WriteToFile(files)
+	}
+	return WriteOutputs(format, output, files)
+}
+
+// ManifestOutputs returns the files of the manifest generators found on disk, the files recorded as generated for the
+// output pattern or the output file
+func ManifestOutputs(format, output string) ([]string, error) {
+	if IsOutputPattern(output) {
+		return RecordedOutputs(format, output)
+	}
+	return Existing(output)
+}
+
+// WriteOutputs writes the files generated for the output, an output directory or an output pattern, then removes the
+// files previously generated for it which aren't generated anymore, along with their emptied directories.
+// The generated files are recorded in the OutputsRecord of the output directory.
+func WriteOutputs(format, output string, files map[string][]byte) error {
+	previous, err := RecordedOutputs(format, output)
+	if err != nil {
+		return err
+	}
+	if err := WriteToFile(files); err != nil {
+		return err
+	}
+
+	dir := recordDir(output)
+	generated := make(map[string]struct{}, len(files))
+	for file := range files {
+		generated[filepath.Clean(file)] = struct{}{}
+	}
+	var stale []string
+	for _, file := range previous {
+		if _, ok := generated[file]; !ok {
+			stale = append(stale, file)
+		}
+	}
+	if err := Clean(stale...); err != nil {
+		return err
+	}
+	for _, file := range stale {
+		// the directories are only removed if they're empty
+		for parent := filepath.Dir(file); parent != dir && parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+			if os.Remove(parent) != nil {
+				break
+			}
+		}
+	}
+	return RecordOutputs(format, output, files)
+}
+
+// RecordedOutputs returns the files recorded as generated for the output which are still on disk, sorted by path
+func RecordedOutputs(format, output string) ([]string, error) {
+	dir := recordDir(output)
+	record, err := readRecord(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, rel := range record[recordKey(format, output)] {
+		rel = filepath.FromSlash(rel)
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			// the record was edited by hand, the files outside of its directory aren't removed
+			continue
+		}
+		files = append(files, filepath.Join(dir, rel))
+	}
+	files, err = Existing(files...)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RecordOutputs records the files generated for the output, replacing the files previously recorded for it.
+// The record is removed once it doesn't record any file.
+func RecordOutputs(format, output string, files map[string][]byte) error {
+	dir := recordDir(output)
+	record, err := readRecord(dir)
+	if err != nil {
+		return err
+	}
+
+	var generated []string
+	for file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return errors.Annotatef(err, "the generated file %q isn't in the output directory %q", file, dir)
+		}
+		generated = append(generated, filepath.ToSlash(rel))
+	}
+	sort.Strings(generated)
+
+	key := recordKey(format, output)
+	if len(generated) == 0 {
+		delete(record, key)
+	} else {
+		record[key] = generated
+	}
+
+	path := filepath.Join(dir, OutputsRecord)
+	if len(record) == 0 {
+		return Clean(path)
+	}
+	body, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(body, '\n'), 0644)
+}
+
+// readRecord reads the OutputsRecord of the directory, the record is empty if the file doesn't exist
+func readRecord(dir string) (map[string][]string, error) {
+	record := map[string][]string{}
+	path := filepath.Join(dir, OutputsRecord)
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return record, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &record); err != nil {
+		return nil, errors.Annotatef(err, "invalid record of the generated files %q", path)
+	}
+	return record, nil
+}
+
+// recordDir returns the directory of the record of the output: the output directory, or the deepest directory of an
+// output pattern not depending on the application name, i.e: docs for docs/{name}/errors.yaml
+func recordDir(output string) string {
+	if i := strings.Index(output, NamePlaceholder); i >= 0 {
+		return filepath.Dir(output[:i] + "x")
+	}
+	return filepath.Clean(output)
+}
+
+// recordKey returns the key of the files generated for the output in the record of its directory, i.e: yaml {name}.yaml
+func recordKey(format, output string) string {
+	rel, err := filepath.Rel(recordDir(output), output)
+	if err != nil {
+		rel = output
+	}
+	return format + " " + filepath.ToSlash(rel)
+}
@@ -0,0 +1,246 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/parser/generate/helpers"
+	"github.com/tfadeyi/errors/pkg/api"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+//go:embed templates
+var theme embed.FS
+
+const (
+	indexTemplate       = "index.html.tmpl"
+	applicationTemplate = "application.html.tmpl"
+	errorTemplate       = "error.html.tmpl"
+	layoutTemplate      = "layout.html.tmpl"
+
+	// page is the file served for the clean URL of its directory, i.e: /{name}/errors/{code}/
+	page = "index.html"
+	// errorsPath is the parent path of the error pages, matching the default path of the error URLs
+	errorsPath = "errors"
+	// format is the key of the generated files in the helpers.OutputsRecord
+	format = "html"
+)
+
+// assets are the static files of the theme copied to the root of the site
+var assets = []string{"style.css", "search.js"}
+
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// ThemeFiles returns the names of the files of the default theme, a file with the same name in the template
+// directory overrides it
+func ThemeFiles() []string {
+	return append([]string{layoutTemplate, indexTemplate, applicationTemplate, errorTemplate}, assets...)
+}
+
+type Generator struct {
+	logger      *logging.Logger
+	output      string
+	writer      io.Writer
+	templateDir string
+	sourceURL   string
+}
+
+// Options contains the configuration options available to the Generator
+type Options struct {
+	Logger *logging.Logger
+	Writer io.Writer
+	Output string
+	// TemplateDir is the directory of the files overriding the files of the default theme, see ThemeFiles
+	TemplateDir string
+	// SourceURL is the repository URL template the errors are linked to their source with, see helpers.SourceURL
+	SourceURL string
+}
+
+// data is the data the pages are rendered with, Root is the relative path from the page to the root of the site
+type data struct {
+	Title        string
+	Root         string
+	Applications []*api.Manifest
+	Application  *api.Manifest
+	Error        api.Error
+}
+
+func New(opts *Options) *Generator {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+
+	return &Generator{
+		logger:      opts.Logger,
+		output:      opts.Output,
+		writer:      opts.Writer,
+		templateDir: opts.TemplateDir,
+		sourceURL:   opts.SourceURL,
+	}
+}
+
+func (g *Generator) Generate(ctx context.Context, specs map[string]any) error {
+	files, err := g.Render(ctx, specs)
+	if err != nil {
+		return err
+	}
+	if g.output == "" {
+		return helpers.Write(g.writer, files)
+	}
+	// remove the pages of the errors that are no longer defined, and their directories
+	return helpers.WriteOutputs(format, g.output, files)
+}
+
+// Outputs returns the files previously generated in the output directory, i.e: index.html, {name}/index.html and
+// {name}/errors/{code}/index.html. The generated files are recorded in the output directory, the other files of the
+// directory aren't outputs, see helpers.OutputsRecord.
+func (g *Generator) Outputs() ([]string, error) {
+	if g.output == "" {
+		return nil, nil
+	}
+	return helpers.RecordedOutputs(format, g.output)
+}
+
+// Render returns the files of the static site generated from the given specs, keyed by their path in the output
+// directory. Each page is an index.html file so the site is served with clean URLs, i.e: /{name}/errors/{code}/.
+// Nothing is written to the generator's writer or output.
+func (g *Generator) Render(ctx context.Context, specs map[string]any) (map[string][]byte, error) {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var applications []*api.Manifest
+	for _, name := range names {
+		spec, ok := specs[name].(*api.Manifest)
+		if !ok {
+			return nil, errors.New("found invalid application errors manifest")
+		}
+		applications = append(applications, spec)
+	}
+
+	tmpl, err := g.templates()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	render := func(path, name string, page data) error {
+		buf := bytes.NewBuffer([]byte{})
+		if err := tmpl.ExecuteTemplate(buf, name, page); err != nil {
+			return errors.Annotatef(err, "could not render %q", path)
+		}
+		files[path] = buf.Bytes()
+		return nil
+	}
+
+	if err := render(filepath.Join(g.output, page), indexTemplate, data{
+		Title:        "Errors",
+		Root:         "./",
+		Applications: applications,
+	}); err != nil {
+		return nil, err
+	}
+	for _, application := range applications {
+		if err := helpers.ValidateFileName(application.Name); err != nil {
+			return nil, errors.Annotate(err, "invalid application name")
+		}
+		if err := render(filepath.Join(g.output, application.Name, page), applicationTemplate, data{
+			Title:        application.Name,
+			Root:         "../",
+			Applications: []*api.Manifest{application},
+			Application:  application,
+		}); err != nil {
+			return nil, err
+		}
+		for code, definition := range application.ErrorsDefinitions {
+			if err := helpers.ValidateFileName(code); err != nil {
+				return nil, errors.Annotatef(err, "invalid error code of application %q", application.Name)
+			}
+			if err := render(filepath.Join(g.output, application.Name, errorsPath, code, page), errorTemplate, data{
+				Title:       definition.Title,
+				Root:        "../../../",
+				Application: application,
+				Error:       definition,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, asset := range assets {
+		body, err := g.themeFile(asset)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(g.output, asset)] = body
+	}
+	return files, nil
+}
+
+// templates returns the page templates of the default theme, overridden by the templates of the template directory
+func (g *Generator) templates() (*template.Template, error) {
+	tmpl := template.New("site").Funcs(g.funcs())
+	for _, name := range []string{layoutTemplate, indexTemplate, applicationTemplate, errorTemplate} {
+		body, err := g.themeFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.New(name).Parse(string(body)); err != nil {
+			return nil, errors.Annotatef(err, "could not parse the template %q", name)
+		}
+	}
+	return tmpl, nil
+}
+
+// themeFile returns the file of the template directory, or the file of the default theme if it isn't overridden
+func (g *Generator) themeFile(name string) ([]byte, error) {
+	if g.templateDir != "" {
+		body, err := os.ReadFile(filepath.Join(g.templateDir, name))
+		if err == nil {
+			return body, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, errors.Annotatef(err, "could not read the template %q", name)
+		}
+	}
+	return theme.ReadFile("templates/" + name)
+}
+
+// funcs returns the functions available to the page templates:
+//   - markdown returns the sanitized HTML of the given markdown text, i.e: the long descriptions
+//   - sourceURL returns the link to the source of the given error, or an empty string if no repository URL template was set
+func (g *Generator) funcs() template.FuncMap {
+	return template.FuncMap{
+		"markdown": func(text any) (template.HTML, error) {
+			var source string
+			switch value := text.(type) {
+			case string:
+				source = value
+			case *string:
+				if value != nil {
+					source = *value
+				}
+			}
+			buf := bytes.NewBuffer([]byte{})
+			if err := markdownRenderer.Convert([]byte(source), buf); err != nil {
+				return "", err
+			}
+			return template.HTML(bluemonday.UGCPolicy().SanitizeBytes(buf.Bytes())), nil
+		},
+		"sourceURL": func(definition api.Error) string {
+			return helpers.SourceURL(g.sourceURL, definition)
+		},
+	}
+}
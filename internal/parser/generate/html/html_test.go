@@ -0,0 +1,157 @@
+package html
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	specs := func() map[string]any {
+		line, long, solution := 12, "The resource wasn't found:\n\n- check the name\n\n<script>alert(1)</script>", "Retry."
+		return map[string]any{
+			"app": &api.Manifest{Name: "app", Version: "v1", ErrorsDefinitions: api.ErrorDefinitions{
+				"not_found": {
+					Code:      "not_found",
+					Title:     "Not Found",
+					Short:     "The resource <b>wasn't</b> found.",
+					Long:      &long,
+					Metadata:  api.ErrorMetadata{"owner": "team-storage"},
+					Solutions: api.Solutions{"retry": {Code: "retry", Short: "Retry the request.", Long: &solution}},
+					Meta:      &api.ErrorMeta{Loc: &api.ErrorMetaLoc{Path: "store/store.go", Line: &line}},
+				},
+				"timeout": {Code: "timeout", Title: "Timeout", Short: "The request timed out."},
+			}},
+		}
+	}
+
+	t.Run("Successfully render a page per error with clean URLs", func(t *testing.T) {
+		t.Parallel()
+		files, err := New(&Options{
+			Output:    "site",
+			SourceURL: "https://github.com/org/repo/blob/main/{path}#L{line}",
+		}).Render(context.Background(), specs())
+		require.NoError(t, err)
+
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, filepath.ToSlash(path))
+		}
+		assert.ElementsMatch(t, []string{
+			"site/index.html",
+			"site/style.css",
+			"site/search.js",
+			"site/app/index.html",
+			"site/app/errors/not_found/index.html",
+			"site/app/errors/timeout/index.html",
+		}, paths)
+
+		index := string(files[filepath.Join("site", "index.html")])
+		assert.Contains(t, index, `<input id="search"`)
+		assert.Contains(t, index, `<a href="./app/errors/not_found/"><code>not_found</code> Not Found</a>`)
+		assert.Contains(t, index, `<script src="./search.js"></script>`)
+
+		page := string(files[filepath.Join("site", "app", "errors", "not_found", "index.html")])
+		assert.Contains(t, page, `<link rel="stylesheet" href="../../../style.css">`)
+		assert.Contains(t, page, "<p>The resource &lt;b&gt;wasn&#39;t&lt;/b&gt; found.</p>")
+		assert.Contains(t, page, "<li>check the name</li>")
+		assert.NotContains(t, page, "alert(1)")
+		assert.Contains(t, page, `<section id="retry">`)
+		assert.Contains(t, page, "<p>Retry.</p>")
+		assert.Contains(t, page, "<dt>owner</dt>")
+		assert.Contains(t, page, `<a href="https://github.com/org/repo/blob/main/store/store.go#L12">View the source</a>`)
+	})
+	t.Run("Successfully override the default theme with the template directory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "error.html.tmpl"), []byte(`{{ template "header" . }}<p>{{ .Error.Code }}: {{ .Application.Name }}</p>{{ template "footer" . }}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "style.css"), []byte("body { color: red; }\n"), 0644))
+
+		files, err := New(&Options{Output: "site", TemplateDir: dir}).Render(context.Background(), specs())
+		require.NoError(t, err)
+		assert.Contains(t, string(files[filepath.Join("site", "app", "errors", "timeout", "index.html")]), "<p>timeout: app</p>")
+		assert.Equal(t, "body { color: red; }\n", string(files[filepath.Join("site", "style.css")]))
+		// the files which aren't overridden come from the default theme
+		assert.Contains(t, string(files[filepath.Join("site", "index.html")]), `<input id="search"`)
+	})
+	t.Run("Successfully remove the pages of the errors no longer defined", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		generator := New(&Options{Output: dir})
+		require.NoError(t, generator.Generate(context.Background(), specs()))
+
+		updated := specs()
+		delete(updated["app"].(*api.Manifest).ErrorsDefinitions, "timeout")
+		require.NoError(t, generator.Generate(context.Background(), updated))
+		_, err := os.Stat(filepath.Join(dir, "app", "errors", "timeout"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		_, err = os.Stat(filepath.Join(dir, "app", "errors", "not_found", "index.html"))
+		assert.NoError(t, err)
+	})
+	t.Run("Successfully keep the pages of the output directory which weren't generated", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		unrelated := []string{
+			filepath.Join(dir, "guide", "index.html"),
+			filepath.Join(dir, "app", "errors", "faq", "index.html"),
+		}
+		for _, path := range unrelated {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte("<p>written by hand</p>\n"), 0644))
+		}
+
+		generator := New(&Options{Output: dir})
+		require.NoError(t, generator.Generate(context.Background(), specs()))
+		require.NoError(t, generator.Generate(context.Background(), map[string]any{}))
+		for _, path := range unrelated {
+			_, err := os.Stat(path)
+			assert.NoError(t, err)
+		}
+		_, err := os.Stat(filepath.Join(dir, "app", "errors", "not_found"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		outputs, err := generator.Outputs()
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "index.html"), filepath.Join(dir, "search.js"), filepath.Join(dir, "style.css")}, outputs)
+	})
+	t.Run("Successfully escape the markup injected by the annotations", func(t *testing.T) {
+		t.Parallel()
+		long := "See [the docs](javascript:alert(3)) <img src=x onerror=alert(4)>"
+		spec := &api.Manifest{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{
+			"bad": {Code: "bad", Title: "<script>alert(1)</script>", Short: `<a href="javascript:alert(2)">x</a>`, Long: &long},
+		}}
+		files, err := New(&Options{Output: "site"}).Render(context.Background(), map[string]any{"app": spec})
+		require.NoError(t, err)
+		for _, path := range []string{filepath.Join("site", "index.html"), filepath.Join("site", "app", "index.html"), filepath.Join("site", "app", "errors", "bad", "index.html")} {
+			page := string(files[path])
+			assert.NotContains(t, page, "<script>alert", path)
+			assert.NotContains(t, page, `href="javascript:`, path)
+			assert.NotContains(t, page, "onerror", path)
+		}
+		assert.Contains(t, string(files[filepath.Join("site", "app", "errors", "bad", "index.html")]), "&lt;script&gt;alert(1)&lt;/script&gt;")
+	})
+	t.Run("Fail to render the pages outside of the output directory", func(t *testing.T) {
+		t.Parallel()
+		for _, spec := range []*api.Manifest{
+			{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{"../../x": {Code: "../../x", Title: "X", Short: "X."}}},
+			{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{"..": {Code: "..", Title: "X", Short: "X."}}},
+			{Name: `..\app`},
+		} {
+			_, err := New(&Options{Output: "site"}).Render(context.Background(), map[string]any{spec.Name: spec})
+			assert.Error(t, err)
+		}
+	})
+	t.Run("Fail to render an invalid template", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html.tmpl"), []byte("{{ .Missing "), 0644))
+		_, err := New(&Options{Output: "site", TemplateDir: dir}).Render(context.Background(), specs())
+		assert.Error(t, err)
+	})
+}
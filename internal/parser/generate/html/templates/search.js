@@ -0,0 +1,25 @@
+(function () {
+  var input = document.getElementById("search");
+  if (!input) {
+    return;
+  }
+  var items = document.querySelectorAll("[data-search]");
+
+  function filter() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    items.forEach(function (item) {
+      var text = item.getAttribute("data-search").toLowerCase();
+      item.hidden = !terms.every(function (term) {
+        return text.indexOf(term) !== -1;
+      });
+    });
+  }
+
+  // the search can be linked to, i.e: ?q=not_found
+  var query = new URLSearchParams(window.location.search).get("q");
+  if (query) {
+    input.value = query;
+  }
+  input.addEventListener("input", filter);
+  filter();
+})();
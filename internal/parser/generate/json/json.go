@@ -0,0 +1,116 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/parser/generate/helpers"
+)
+
+const (
+	// indent is the indentation of the pretty printed manifests
+	indent = "  "
+	// format is the key of the generated files in the helpers.OutputsRecord
+	format = "json"
+)
+
+type Generator struct {
+	logger  *logging.Logger
+	writer  io.Writer
+	output  string
+	compact bool
+}
+
+// Options contains the configuration options available to the Generator
+type Options struct {
+	Logger *logging.Logger
+	Writer io.Writer
+	Output string
+	// Compact configures the generator to print each manifest on a single line, rather than pretty printing it
+	Compact bool
+}
+
+func New(opts *Options) *Generator {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+	return &Generator{
+		logger:  opts.Logger,
+		writer:  opts.Writer,
+		output:  opts.Output,
+		compact: opts.Compact,
+	}
+}
+
+func (g *Generator) Generate(ctx context.Context, specs map[string]any) error {
+	files, err := g.Render(ctx, specs)
+	if err != nil {
+		return err
+	}
+	return helpers.GenerateManifests(format, g.writer, g.output, files)
+}
+
+// Render returns the JSON manifest generated from the given specs, keyed by the output file.
+// Several specs are rendered as a stream of JSON documents sorted by application name, unless the output contains
+// the helpers.NamePlaceholder, then each application is rendered to its own file.
+// Nothing is written to the generator's writer or output.
+func (g *Generator) Render(ctx context.Context, specs map[string]any) (map[string][]byte, error) {
+	if !helpers.IsOutputPattern(g.output) {
+		body, err := g.render(specs)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{g.output: body}, nil
+	}
+
+	files := make(map[string][]byte, len(specs))
+	for name, spec := range specs {
+		if err := helpers.ValidateFileName(name); err != nil {
+			return nil, errors.Annotate(err, "invalid application name")
+		}
+		output := helpers.ExpandOutput(g.output, name)
+		if _, ok := files[output]; ok {
+			return nil, errors.Errorf("the applications can't be rendered to %q, several application names resolve to %q", g.output, output)
+		}
+		body, err := g.render(map[string]any{name: spec})
+		if err != nil {
+			return nil, err
+		}
+		files[output] = body
+	}
+	return files, nil
+}
+
+// render returns the JSON documents of the given specs sorted by application name, each followed by a new line
+func (g *Generator) render(specs map[string]any) ([]byte, error) {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if !g.compact {
+		encoder.SetIndent("", indent)
+	}
+	for _, name := range names {
+		if err := encoder.Encode(specs[name]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Outputs returns the generated files found on disk, if any. The files generated for the output pattern are recorded
+// in its directory if the output contains the helpers.NamePlaceholder, see helpers.OutputsRecord.
+func (g *Generator) Outputs() ([]string, error) {
+	return helpers.ManifestOutputs(format, g.output)
+}
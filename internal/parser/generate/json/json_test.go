@@ -0,0 +1,197 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// validate checks the document against the subset of the JSON schema draft used by schema/schema.json
+func validate(definitions, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return validate(definitions, definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]any), value, path)
+	}
+
+	var problems []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object", path)}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]any); ok {
+				problems = append(problems, validate(definitions, property, object[key], path+"."+key)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s: unexpected property %q", path, key))
+				}
+			case map[string]any:
+				problems = append(problems, validate(definitions, additional, object[key], path+"."+key)...)
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected a string", path)}
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && float64(len(text)) > maxLength {
+			problems = append(problems, fmt.Sprintf("%s: longer than %v", path, maxLength))
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return []string{fmt.Sprintf("%s: expected an integer", path)}
+		}
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			problems = append(problems, fmt.Sprintf("%s: lower than %v", path, minimum))
+		}
+	}
+	return problems
+}
+
+// decodeStream decodes the JSON documents of the stream
+func decodeStream(t *testing.T, body []byte) []any {
+	var documents []any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var document any
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return documents
+		}
+		require.NoError(t, err)
+		documents = append(documents, document)
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	body, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "schema", "schema.json"))
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(body, &schema))
+	definitions := schema["definitions"].(map[string]any)
+
+	specs := func() map[string]any {
+		line, column, long, pkg := 12, 2, "The <resource> wasn't found.", "github.com/org/app/store"
+		return map[string]any{
+			"web": &api.Manifest{Name: "web", Version: "v1", BaseUrl: "https://example.com", ErrorsDefinitions: api.ErrorDefinitions{
+				"timeout": {Code: "timeout", Title: "Timeout", Short: "Timeout."},
+				"not_found": {
+					Code:      "not_found",
+					Title:     "Not Found",
+					Short:     "Not found.",
+					Long:      &long,
+					Metadata:  api.ErrorMetadata{"owner": "team-storage"},
+					Solutions: api.Solutions{"retry": {Code: "retry", Short: "Retry."}},
+					Meta:      &api.ErrorMeta{Package: &pkg, Loc: &api.ErrorMetaLoc{Path: "store/store.go", Line: &line, Column: &column}},
+				},
+			}},
+			"cli": &api.Manifest{Name: "cli", Version: "v1"},
+		}
+	}
+
+	t.Run("Successfully render the applications as a stable stream of valid documents", func(t *testing.T) {
+		t.Parallel()
+		files, err := New(&Options{Output: "errors.json"}).Render(context.Background(), specs())
+		require.NoError(t, err)
+
+		documents := decodeStream(t, files["errors.json"])
+		require.Len(t, documents, 2)
+		assert.Equal(t, "cli", documents[0].(map[string]any)["name"])
+		assert.Equal(t, "web", documents[1].(map[string]any)["name"])
+		for _, document := range documents {
+			assert.Empty(t, validate(definitions, schema, document, "$"))
+		}
+		assert.Equal(t, []string{`$: missing required property "base_url"`, `$: unexpected property "owner"`},
+			validate(definitions, schema, map[string]any{"name": "web", "version": "v1", "owner": "team"}, "$"))
+		assert.Contains(t, string(files["errors.json"]), "\n  \"name\": \"web\",\n")
+		assert.Contains(t, string(files["errors.json"]), "The <resource> wasn't found.")
+
+		for i := 0; i < 10; i++ {
+			again, err := New(&Options{Output: "errors.json"}).Render(context.Background(), specs())
+			require.NoError(t, err)
+			assert.Equal(t, files, again)
+		}
+	})
+	t.Run("Successfully render the applications on a single line each", func(t *testing.T) {
+		t.Parallel()
+		files, err := New(&Options{Compact: true}).Render(context.Background(), specs())
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(string(files[""]), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, `{"base_url":"","name":"cli","version":"v1"}`, lines[0])
+		assert.Len(t, decodeStream(t, files[""]), 2)
+	})
+	t.Run("Successfully render each application to its own file", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		generator := New(&Options{Output: filepath.Join(dir, "errors-{name}.json")})
+		legacy := specs()
+		legacy["legacy"] = &api.Manifest{Name: "legacy", Version: "v1"}
+		require.NoError(t, generator.Generate(context.Background(), legacy))
+		require.NoError(t, generator.Generate(context.Background(), specs()))
+		outputs, err := generator.Outputs()
+		require.NoError(t, err)
+		// the files of the applications which aren't generated anymore are removed
+		assert.Equal(t, []string{filepath.Join(dir, "errors-cli.json"), filepath.Join(dir, "errors-web.json")}, outputs)
+		_, err = os.Stat(filepath.Join(dir, "errors-legacy.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		body, err := os.ReadFile(filepath.Join(dir, "errors-web.json"))
+		require.NoError(t, err)
+		var manifest api.Manifest
+		require.NoError(t, json.Unmarshal(body, &manifest))
+		assert.Equal(t, "web", manifest.Name)
+		assert.Len(t, manifest.ErrorsDefinitions, 2)
+	})
+	t.Run("Successfully keep the files of the output directory which weren't generated", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		unrelated := []string{filepath.Join(dir, "package.json"), filepath.Join(dir, "tsconfig.json")}
+		for _, path := range unrelated {
+			require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+		}
+
+		generator := New(&Options{Output: filepath.Join(dir, "{name}.json")})
+		require.NoError(t, generator.Generate(context.Background(), specs()))
+		require.NoError(t, generator.Generate(context.Background(), map[string]any{}))
+		for _, path := range unrelated {
+			_, err := os.Stat(path)
+			assert.NoError(t, err)
+		}
+		outputs, err := generator.Outputs()
+		require.NoError(t, err)
+		assert.Empty(t, outputs)
+	})
+}
@@ -22,11 +22,25 @@ var errorDefinitionMarkdownTmpl string
 //go:embed templates/info.md.tmpl
 var applicationInfoMarkdownTmpl string
 
+// DefaultInfoTemplate returns the default go-template used to generate the application information markdown
+func DefaultInfoTemplate() string {
+	return applicationInfoMarkdownTmpl
+}
+
+// DefaultErrorTemplate returns the default go-template used to generate the error definition markdown
+func DefaultErrorTemplate() string {
+	return errorDefinitionMarkdownTmpl
+}
+
+// format is the key of the generated files in the helpers.OutputsRecord
+const format = "markdown"
+
 type Generator struct {
 	logger                      *logging.Logger
 	output                      string
 	writer                      io.Writer
 	infoTmplFile, errorTmplFile string
+	sourceURL                   string
 }
 
 // Options contains the configuration options available to the Generator
@@ -35,6 +49,8 @@ type Options struct {
 	Writer                      io.Writer
 	Output                      string
 	InfoTmplFile, ErrorTmplFile string
+	// SourceURL is the repository URL template the errors are linked to their source with, see helpers.SourceURL
+	SourceURL string
 }
 
 func New(opts *Options) *Generator {
@@ -49,55 +65,75 @@ func New(opts *Options) *Generator {
 		writer:        opts.Writer,
 		infoTmplFile:  opts.InfoTmplFile,
 		errorTmplFile: opts.ErrorTmplFile,
+		sourceURL:     opts.SourceURL,
 	}
 }
 
 func (g *Generator) Generate(ctx context.Context, specs map[string]any) error {
-	return writeMarkdownSpecifications(g.writer, specs, g.output != "", g.output, g.infoTmplFile, g.errorTmplFile)
+	files, err := g.Render(ctx, specs)
+	if err != nil {
+		return err
+	}
+	if g.output == "" {
+		return helpers.Write(g.writer, files)
+	}
+	// remove the docs of the errors that are no longer defined
+	return helpers.WriteOutputs(format, g.output, files)
+}
+
+// Outputs returns the markdown files previously generated in the output directory, i.e: index.md and errors/*.md.
+// The generated files are recorded in the output directory, the other files of the directory aren't outputs, see
+// helpers.OutputsRecord.
+func (g *Generator) Outputs() ([]string, error) {
+	if g.output == "" {
+		return nil, nil
+	}
+	return helpers.RecordedOutputs(format, g.output)
 }
 
-func writeMarkdownSpecifications(writer io.Writer, specs map[string]any, toFile bool, outputDirectory string, infoTmpl, errorTmpl string) error {
+// Render returns the markdown files generated from the given specs, keyed by their path in the output directory.
+// Nothing is written to the generator's writer or output.
+func (g *Generator) Render(ctx context.Context, specs map[string]any) (map[string][]byte, error) {
+	files := make(map[string][]byte)
 	for _, spec := range specs {
-		foundSpec, ok := spec.(*api.Manifest)
-		if !ok {
-			return errors.New("found invalid application errors manifest")
+		found, err := renderMarkdownSpecification(spec, g.output, g.infoTmplFile, g.errorTmplFile, g.funcs())
+		if err != nil {
+			return nil, err
 		}
-
-		var files map[string][]byte
-		var err error
-
-		if infoTmpl != "" && errorTmpl != "" {
-			files, err = generateMarkdownWithCustomTemplates(foundSpec, outputDirectory, infoTmpl, errorTmpl)
-			if err != nil {
-				return err
-			}
-		} else {
-			files, err = generateMarkdown(foundSpec, outputDirectory)
-			if err != nil {
-				return err
-			}
+		for path, body := range found {
+			files[path] = body
 		}
+	}
+	return files, nil
+}
 
-		if toFile {
-			if err := helpers.WriteToFile(files); err != nil {
-				return err
-			}
-			continue
-		}
+// funcs returns the functions available to the markdown templates:
+//   - sourceURL returns the link to the source of the given error, or an empty string if no repository URL template was set
+func (g *Generator) funcs() template.FuncMap {
+	return template.FuncMap{
+		"sourceURL": func(definition api.Error) string {
+			return helpers.SourceURL(g.sourceURL, definition)
+		},
+	}
+}
 
-		if err := helpers.Write(writer, files); err != nil {
-			return err
-		}
+func renderMarkdownSpecification(spec any, outputDirectory string, infoTmpl, errorTmpl string, funcs template.FuncMap) (map[string][]byte, error) {
+	foundSpec, ok := spec.(*api.Manifest)
+	if !ok {
+		return nil, errors.New("found invalid application errors manifest")
 	}
 
-	return nil
+	if infoTmpl != "" && errorTmpl != "" {
+		return generateMarkdownWithCustomTemplates(foundSpec, outputDirectory, infoTmpl, errorTmpl, funcs)
+	}
+	return generateMarkdown(foundSpec, outputDirectory, funcs)
 }
 
-func generateMarkdown(spec *api.Manifest, outputDir string) (map[string][]byte, error) {
+func generateMarkdown(spec *api.Manifest, outputDir string, funcs template.FuncMap) (map[string][]byte, error) {
 	files := make(map[string][]byte)
 	root := filepath.Join(outputDir, "index.md")
 	// parse application general information
-	tmpl, err := template.New(spec.Name).Parse(applicationInfoMarkdownTmpl)
+	tmpl, err := template.New(spec.Name).Funcs(funcs).Parse(applicationInfoMarkdownTmpl)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +147,10 @@ func generateMarkdown(spec *api.Manifest, outputDir string) (map[string][]byte,
 	}
 
 	for code, def := range spec.ErrorsDefinitions {
-		tmpl, err := template.New(code).Parse(errorDefinitionMarkdownTmpl)
+		if err := helpers.ValidateFileName(code); err != nil {
+			return nil, errors.Annotatef(err, "invalid error code of application %q", spec.Name)
+		}
+		tmpl, err := template.New(code).Funcs(funcs).Parse(errorDefinitionMarkdownTmpl)
 		if err != nil {
 			return nil, err
 		}
@@ -128,11 +167,11 @@ func generateMarkdown(spec *api.Manifest, outputDir string) (map[string][]byte,
 	return files, nil
 }
 
-func generateMarkdownWithCustomTemplates(spec *api.Manifest, outputDir string, infoTmplFile, errorTmplFile string) (map[string][]byte, error) {
+func generateMarkdownWithCustomTemplates(spec *api.Manifest, outputDir string, infoTmplFile, errorTmplFile string, funcs template.FuncMap) (map[string][]byte, error) {
 	files := make(map[string][]byte)
 	root := filepath.Join(outputDir, "index.md")
 	// parse application general information
-	tmpl, err := template.New(spec.Name).ParseFiles(infoTmplFile)
+	tmpl, err := template.New(spec.Name).Funcs(funcs).ParseFiles(infoTmplFile)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +185,10 @@ func generateMarkdownWithCustomTemplates(spec *api.Manifest, outputDir string, i
 	}
 
 	for code, def := range spec.ErrorsDefinitions {
-		tmpl, err := template.New(code).ParseFiles(errorTmplFile)
+		if err := helpers.ValidateFileName(code); err != nil {
+			return nil, errors.Annotatef(err, "invalid error code of application %q", spec.Name)
+		}
+		tmpl, err := template.New(code).Funcs(funcs).ParseFiles(errorTmplFile)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully render a doc per error", func(t *testing.T) {
+		t.Parallel()
+		spec := &api.Manifest{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{
+			"not_found": {Code: "not_found", Title: "Not Found", Short: "Not found."},
+		}}
+		files, err := New(&Options{Output: "docs"}).Render(context.Background(), map[string]any{"app": spec})
+		require.NoError(t, err)
+		assert.Contains(t, files, filepath.Join("docs", "index.md"))
+		assert.Contains(t, string(files[filepath.Join("docs", "errors", "not_found.md")]), "## Not Found")
+	})
+	t.Run("Successfully remove the docs previously generated only", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		faq := filepath.Join(dir, "errors", "faq.md")
+		require.NoError(t, os.MkdirAll(filepath.Dir(faq), 0755))
+		require.NoError(t, os.WriteFile(faq, []byte("# FAQ\n"), 0644))
+
+		spec := func(codes ...string) map[string]any {
+			definitions := api.ErrorDefinitions{}
+			for _, code := range codes {
+				definitions[code] = api.Error{Code: code, Title: code, Short: code + "."}
+			}
+			return map[string]any{"app": &api.Manifest{Name: "app", ErrorsDefinitions: definitions}}
+		}
+		generator := New(&Options{Output: dir})
+		require.NoError(t, generator.Generate(context.Background(), spec("not_found", "timeout")))
+		require.NoError(t, generator.Generate(context.Background(), spec("not_found")))
+
+		outputs, err := generator.Outputs()
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "errors", "not_found.md"), filepath.Join(dir, "index.md")}, outputs)
+		_, err = os.Stat(faq)
+		assert.NoError(t, err)
+	})
+	t.Run("Successfully remove the markup injected by the annotations", func(t *testing.T) {
+		t.Parallel()
+		long := "Some <b>bold</b> <script>alert(4)</script> text."
+		spec := &api.Manifest{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{
+			"bad": {Code: "bad", Title: "<script>alert(1)</script> Bad", Short: "Use <img src=x onerror=alert(2)> here.", Long: &long},
+		}}
+		files, err := New(&Options{Output: "docs"}).Render(context.Background(), map[string]any{"app": spec})
+		require.NoError(t, err)
+		for path, body := range files {
+			assert.NotContains(t, string(body), "<script", path)
+			assert.NotContains(t, string(body), "onerror", path)
+		}
+		assert.Contains(t, string(files[filepath.Join("docs", "errors", "bad.md")]), "<b>bold</b>")
+	})
+	t.Run("Fail to render the docs outside of the output directory", func(t *testing.T) {
+		t.Parallel()
+		for _, code := range []string{"../../x", "..", "a/b", `a\b`} {
+			spec := &api.Manifest{Name: "app", ErrorsDefinitions: api.ErrorDefinitions{
+				code: {Code: code, Title: "X", Short: "X."},
+			}}
+			_, err := New(&Options{Output: "docs"}).Render(context.Background(), map[string]any{"app": spec})
+			assert.Error(t, err, code)
+		}
+	})
+}
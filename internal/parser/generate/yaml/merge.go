@@ -0,0 +1,253 @@
+package yaml
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OrphansMark keeps the errors and applications which aren't defined by the source code anymore, marked with
+	// orphanComment
+	OrphansMark = "mark"
+	// OrphansDrop removes the errors and applications which aren't defined by the source code anymore
+	OrphansDrop = "drop"
+
+	// orphanComment marks the errors and applications of the existing manifest which aren't defined by the source
+	// code anymore
+	orphanComment = "# orphaned: not defined by the source code anymore"
+
+	nameKey   = "name"
+	errorsKey = "errors_definitions"
+	// metaKey is the metadata generated from the location of the annotations, it's replaced as a whole
+	metaKey = "meta"
+	// the solutions and metadata of an error are merged the same way as the errors, the keys which aren't generated
+	// anymore are orphaned
+	solutionsKey = "solutions"
+	metadataKey  = "metadata"
+)
+
+// IsSupportedOrphans checks if the given orphans handling mode is a supported one
+func IsSupportedOrphans(mode string) bool {
+	switch mode {
+	case OrphansMark, OrphansDrop:
+		return true
+	}
+	return false
+}
+
+// document is a YAML document of a manifest stream
+type document struct {
+	name string
+	node *yaml.Node
+}
+
+// mergeStream updates the existing manifest stream with the generated documents, keyed by application name, so the
+// changes made by hand are kept: the generated fields replace the existing ones, the other fields, the comments and
+// the key order of the existing manifest are kept. The errors the source code doesn't define anymore are marked or dropped,
+// so are the applications. The documents are returned sorted by application name.
+func (g *Generator) mergeStream(output string, existing []byte, generated map[string]*yaml.Node) ([]document, error) {
+	documents, err := decodeStream(existing)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to decode the existing manifest %q", output)
+	}
+
+	var merged []document
+	seen := map[string]struct{}{}
+	for _, doc := range documents {
+		if _, ok := seen[doc.name]; ok {
+			return nil, errors.Errorf("the application %q is defined twice in the existing manifest %q", doc.name, output)
+		}
+		seen[doc.name] = struct{}{}
+
+		node, ok := generated[doc.name]
+		if !ok {
+			if g.orphans != OrphansDrop {
+				markDocument(doc.node, mark)
+				merged = append(merged, doc)
+			}
+			continue
+		}
+		markDocument(doc.node, unmark)
+		g.mapping(doc.node, node, nil)
+		merged = append(merged, doc)
+	}
+	for name, node := range generated {
+		if _, ok := seen[name]; !ok {
+			merged = append(merged, document{name: name, node: node})
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].name < merged[j].name
+	})
+	return merged, nil
+}
+
+// mapping merges the generated mapping into the existing one, path is the path of the mapping in the document
+func (g *Generator) mapping(existing, generated *yaml.Node, path []string) {
+	index := map[string]int{}
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		index[existing.Content[i].Value] = i
+	}
+
+	generatedKeys := map[string]struct{}{}
+	for i := 0; i+1 < len(generated.Content); i += 2 {
+		key, value := generated.Content[i], generated.Content[i+1]
+		generatedKeys[key.Value] = struct{}{}
+
+		j, ok := index[key.Value]
+		if !ok {
+			existing.Content = append(existing.Content, key, value)
+			continue
+		}
+		existingKey, existingValue := existing.Content[j], existing.Content[j+1]
+		if isOrphanable(path) {
+			existingKey.HeadComment = unmark(existingKey.HeadComment)
+		}
+		if existingValue.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode && key.Value != metaKey {
+			g.mapping(existingValue, value, append(path, key.Value))
+			continue
+		}
+		// the comments of the replaced value are kept
+		value.HeadComment, value.LineComment, value.FootComment = existingValue.HeadComment, existingValue.LineComment, existingValue.FootComment
+		existing.Content[j+1] = value
+	}
+
+	// the errors definitions are omitted when the application doesn't define any error, they're all orphaned, so are
+	// the solutions and metadata of an error
+	emptied := map[string]struct{}{}
+	for _, omitted := range []string{errorsKey, solutionsKey, metadataKey} {
+		j, ok := index[omitted]
+		if !ok || !isOrphanable(append(path, omitted)) {
+			continue
+		}
+		if _, generated := generatedKeys[omitted]; !generated && existing.Content[j+1].Kind == yaml.MappingNode {
+			g.mapping(existing.Content[j+1], &yaml.Node{Kind: yaml.MappingNode}, append(path, omitted))
+			if len(existing.Content[j+1].Content) == 0 {
+				// all of its keys were dropped
+				emptied[omitted] = struct{}{}
+			}
+		}
+	}
+	if len(emptied) > 0 {
+		content := existing.Content[:0]
+		for i := 0; i+1 < len(existing.Content); i += 2 {
+			if _, ok := emptied[existing.Content[i].Value]; !ok {
+				content = append(content, existing.Content[i], existing.Content[i+1])
+			}
+		}
+		existing.Content = content
+	}
+	if !isOrphanable(path) {
+		return
+	}
+	// the keys which aren't generated anymore are orphaned
+	content := existing.Content[:0]
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		key, value := existing.Content[i], existing.Content[i+1]
+		if _, ok := generatedKeys[key.Value]; !ok {
+			if g.orphans == OrphansDrop {
+				continue
+			}
+			key.HeadComment = mark(key.HeadComment)
+		}
+		content = append(content, key, value)
+	}
+	existing.Content = content
+}
+
+// markDocument updates the orphan marker of the application, it's on the head comment of the first key
+func markDocument(node *yaml.Node, update func(comment string) string) {
+	if len(node.Content) > 0 {
+		node.Content[0].HeadComment = update(node.Content[0].HeadComment)
+	}
+}
+
+// isOrphanable checks if the path is the path of a mapping whose keys are orphaned once they aren't generated anymore:
+// the errors definitions, and the solutions and metadata of an error
+func isOrphanable(path []string) bool {
+	switch {
+	case len(path) == 1:
+		return path[0] == errorsKey
+	case len(path) == 3:
+		return path[0] == errorsKey && (path[2] == solutionsKey || path[2] == metadataKey)
+	}
+	return false
+}
+
+// mark adds the orphan marker to the head comment, if it isn't there already
+func mark(comment string) string {
+	if strings.Contains(comment, orphanComment) {
+		return comment
+	}
+	if comment == "" {
+		return orphanComment
+	}
+	return comment + "\n" + orphanComment
+}
+
+// unmark removes the orphan marker from the head comment
+func unmark(comment string) string {
+	lines := strings.Split(comment, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line != orphanComment {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// decodeStream decodes the documents of a YAML stream, keyed by the name of their application
+func decodeStream(body []byte) ([]document, error) {
+	var documents []document
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			return nil, errors.New("the manifest documents must be mappings")
+		}
+		root := doc.Content[0]
+		name := ""
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == nameKey {
+				name = root.Content[i+1].Value
+			}
+		}
+		documents = append(documents, document{name: name, node: root})
+	}
+}
+
+// encode encodes the document the same way yaml.Marshal does, the header is removed from the head comment of the
+// first key, it's written by Render
+func (g *Generator) encode(doc document) ([]byte, error) {
+	if g.header != "" && len(doc.node.Content) > 0 {
+		first := doc.node.Content[0]
+		if strings.HasPrefix(first.HeadComment, g.header) {
+			first.HeadComment = strings.TrimLeft(strings.TrimPrefix(first.HeadComment, g.header), "\n")
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(doc.node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
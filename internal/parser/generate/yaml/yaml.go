@@ -3,17 +3,25 @@ package yaml
 import (
 	"bytes"
 	"context"
+	"github.com/juju/errors"
 	"github.com/tfadeyi/errors/internal/logging"
 	"github.com/tfadeyi/errors/internal/parser/generate/helpers"
 	"gopkg.in/yaml.v3"
 	"io"
+	"os"
+	"sort"
 )
 
+// format is the key of the generated files in the helpers.OutputsRecord
+const format = "yaml"
+
 type Generator struct {
-	logger *logging.Logger
-	writer io.Writer
-	output string
-	header string
+	logger  *logging.Logger
+	writer  io.Writer
+	output  string
+	header  string
+	merge   bool
+	orphans string
 }
 
 // Options contains the configuration options available to the Generator
@@ -22,6 +30,11 @@ type Options struct {
 	Writer io.Writer
 	Output string
 	Header string
+	// Merge configures the generator to merge the generated manifest into the existing Output file, rather than
+	// replacing it, so the fields, comments and key order edited by hand are kept
+	Merge bool
+	// Orphans is how the merge handles the errors missing from the source code, OrphansMark (default) or OrphansDrop
+	Orphans string
 }
 
 func New(opts *Options) *Generator {
@@ -30,43 +43,132 @@ func New(opts *Options) *Generator {
 		opts = new(Options)
 	}
 	return &Generator{
-		logger: opts.Logger,
-		writer: opts.Writer,
-		output: opts.Output,
-		header: opts.Header,
+		logger:  opts.Logger,
+		writer:  opts.Writer,
+		output:  opts.Output,
+		header:  opts.Header,
+		merge:   opts.Merge,
+		orphans: opts.Orphans,
 	}
 }
 
 func (g *Generator) Generate(ctx context.Context, specs map[string]any) error {
-	return writeYAMLSpecifications(g.writer, specs, g.output != "", g.output, g.header)
+	files, err := g.Render(ctx, specs)
+	if err != nil {
+		return err
+	}
+	return helpers.GenerateManifests(format, g.writer, g.output, files)
 }
 
-func writeYAMLSpecifications(writer io.Writer, specs map[string]any, toFile bool, output, header string) error {
-	for _, spec := range specs {
-		var files = make(map[string][]byte)
-
-		body, err := yaml.Marshal(spec)
+// Render returns the YAML manifest generated from the given specs, keyed by the output file.
+// Several specs are rendered as a multi-document stream sorted by application name, unless the output contains
+// the helpers.NamePlaceholder, then each application is rendered to its own file.
+// In merge mode the specs are merged into the existing output files, if any.
+// Nothing is written to the generator's writer or output.
+func (g *Generator) Render(ctx context.Context, specs map[string]any) (map[string][]byte, error) {
+	if !helpers.IsOutputPattern(g.output) {
+		body, err := g.render(g.output, specs)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return map[string][]byte{g.output: body}, nil
+	}
 
-		file := output
-		files[file] = bytes.Join([][]byte{[]byte("---"), []byte(header), body}, []byte("\n"))
-		if err := helpers.Clean(file); err != nil {
-			return err
+	files := make(map[string][]byte, len(specs))
+	for name, spec := range specs {
+		if err := helpers.ValidateFileName(name); err != nil {
+			return nil, errors.Annotate(err, "invalid application name")
 		}
+		output := helpers.ExpandOutput(g.output, name)
+		if _, ok := files[output]; ok {
+			return nil, errors.Errorf("the applications can't be rendered to %q, several application names resolve to %q", g.output, output)
+		}
+		body, err := g.render(output, map[string]any{name: spec})
+		if err != nil {
+			return nil, err
+		}
+		files[output] = body
+	}
+	if !g.merge || g.orphans == OrphansDrop {
+		return files, nil
+	}
 
-		if toFile {
-			if err := helpers.WriteToFile(files); err != nil {
-				return err
-			}
+	// the files of the applications which aren't generated anymore are kept, the applications are marked as orphaned
+	existing, err := g.Outputs()
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range existing {
+		if _, ok := files[output]; ok {
 			continue
 		}
+		body, err := g.render(output, nil)
+		if err != nil {
+			return nil, err
+		}
+		files[output] = body
+	}
+	return files, nil
+}
 
-		if err := helpers.Write(writer, files); err != nil {
-			return err
+// render returns the YAML manifest stream of the given specs sorted by application name, it's merged into the
+// existing output file in merge mode
+func (g *Generator) render(output string, specs map[string]any) ([]byte, error) {
+	if g.merge && output != "" {
+		existing, err := os.ReadFile(output)
+		if err == nil {
+			return g.renderMerged(output, existing, specs)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, errors.Annotatef(err, "failed to read the existing manifest %q", output)
 		}
 	}
 
-	return nil
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var documents [][]byte
+	for _, name := range names {
+		body, err := yaml.Marshal(specs[name])
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, bytes.Join([][]byte{[]byte("---"), []byte(g.header), body}, []byte("\n")))
+	}
+	return bytes.Join(documents, nil), nil
+}
+
+// renderMerged returns the YAML manifest stream of the given specs merged into the existing manifest
+func (g *Generator) renderMerged(output string, existing []byte, specs map[string]any) ([]byte, error) {
+	generated := make(map[string]*yaml.Node, len(specs))
+	for name, spec := range specs {
+		node := new(yaml.Node)
+		if err := node.Encode(spec); err != nil {
+			return nil, err
+		}
+		generated[name] = node
+	}
+
+	merged, err := g.mergeStream(output, existing, generated)
+	if err != nil {
+		return nil, err
+	}
+	var documents [][]byte
+	for _, doc := range merged {
+		body, err := g.encode(doc)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, bytes.Join([][]byte{[]byte("---"), []byte(g.header), body}, []byte("\n")))
+	}
+	return bytes.Join(documents, nil), nil
+}
+
+// Outputs returns the generated files found on disk, if any. The files generated for the output pattern are recorded
+// in its directory if the output contains the helpers.NamePlaceholder, see helpers.OutputsRecord.
+func (g *Generator) Outputs() ([]string, error) {
+	return helpers.ManifestOutputs(format, g.output)
 }
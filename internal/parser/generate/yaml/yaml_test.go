@@ -0,0 +1,372 @@
+package yaml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+const header = "# Code generated by errctl. DO NOT EDIT."
+
+func manifest(codes ...string) map[string]any {
+	definitions := api.ErrorDefinitions{}
+	for _, code := range codes {
+		definitions[code] = api.Error{Code: code, Title: "Title of " + code, Short: "Short of " + code}
+	}
+	return map[string]any{"app": &api.Manifest{Name: "app", Version: "v1", ErrorsDefinitions: definitions}}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	render := func(t *testing.T, opts *Options, specs map[string]any) string {
+		opts.Header = header
+		opts.Merge = true
+		files, err := New(opts).Render(context.Background(), specs)
+		require.NoError(t, err)
+		return string(files[opts.Output])
+	}
+	existing := func(t *testing.T, content string) string {
+		output := filepath.Join(t.TempDir(), "errors.yaml")
+		require.NoError(t, os.WriteFile(output, []byte(content), 0644))
+		return output
+	}
+
+	t.Run("Successfully render the manifest if there is no existing manifest", func(t *testing.T) {
+		t.Parallel()
+		output := filepath.Join(t.TempDir(), "errors.yaml")
+		files, err := New(&Options{Output: output, Header: header}).Render(context.Background(), manifest("not_found"))
+		require.NoError(t, err)
+		assert.Equal(t, string(files[output]), render(t, &Options{Output: output}, manifest("not_found")))
+	})
+	t.Run("Successfully leave an up to date manifest unchanged", func(t *testing.T) {
+		t.Parallel()
+		files, err := New(&Options{Output: "errors.yaml", Header: header}).Render(context.Background(), manifest("not_found", "timeout"))
+		require.NoError(t, err)
+		output := existing(t, string(files["errors.yaml"]))
+		assert.Equal(t, string(files["errors.yaml"]), render(t, &Options{Output: output}, manifest("not_found", "timeout")))
+	})
+	t.Run("Successfully keep the fields edited by hand", func(t *testing.T) {
+		t.Parallel()
+		output := existing(t, `---
+`+header+`
+# reviewed by the docs team
+name: app
+x-owner: team-storage
+version: v0
+errors_definitions:
+    not_found:
+        short: Short of not_found
+        # edited by hand
+        title: Resource Not Found # keep the line comment
+        code: not_found
+        metadata:
+            runbook: https://runbooks/not_found
+`)
+		specs := manifest("not_found", "timeout")
+		specs["app"].(*api.Manifest).ErrorsDefinitions["not_found"] = api.Error{
+			Code: "not_found", Title: "Not Found", Short: "Short of not_found",
+			Metadata: api.ErrorMetadata{"runbook": "https://runbooks/not_found", "tier": "critical"},
+		}
+		assert.Equal(t, `---
+`+header+`
+# reviewed by the docs team
+name: app
+x-owner: team-storage
+version: v1
+errors_definitions:
+    not_found:
+        short: Short of not_found
+        # edited by hand
+        title: Not Found # keep the line comment
+        code: not_found
+        metadata:
+            runbook: https://runbooks/not_found
+            tier: critical
+    timeout:
+        code: timeout
+        short: Short of timeout
+        title: Title of timeout
+base_url: ""
+`, render(t, &Options{Output: output}, specs))
+	})
+	t.Run("Successfully mark the orphaned errors and applications", func(t *testing.T) {
+		t.Parallel()
+		output := existing(t, `---
+`+header+`
+name: app
+version: v1
+errors_definitions:
+    removed:
+        code: removed
+        short: Removed
+        title: Removed
+---
+`+header+`
+name: legacy
+version: v1
+`)
+		merged := render(t, &Options{Output: output}, manifest())
+		assert.Equal(t, `---
+`+header+`
+name: app
+version: v1
+errors_definitions:
+    # orphaned: not defined by the source code anymore
+    removed:
+        code: removed
+        short: Removed
+        title: Removed
+base_url: ""
+---
+`+header+`
+# orphaned: not defined by the source code anymore
+name: legacy
+version: v1
+`, merged)
+
+		// the marker is removed once the error is defined again
+		require.NoError(t, os.WriteFile(output, []byte(merged), 0644))
+		assert.NotContains(t, render(t, &Options{Output: output}, manifest("removed")), "\n    # orphaned")
+	})
+	t.Run("Successfully mark and drop the orphaned solutions and metadata of the errors", func(t *testing.T) {
+		t.Parallel()
+		content := `---
+name: app
+version: v1
+errors_definitions:
+    not_found:
+        code: not_found
+        short: Short of not_found
+        title: Title of not_found
+        solutions:
+            removed:
+                code: removed
+                short: Removed
+            retry:
+                code: retry
+                short: Retry
+        metadata:
+            owner: storage
+            tier: critical
+    timeout:
+        code: timeout
+        short: Short of timeout
+        title: Title of timeout
+        metadata:
+            owner: network
+`
+		specs := manifest("not_found", "timeout")
+		specs["app"].(*api.Manifest).ErrorsDefinitions["not_found"] = api.Error{
+			Code: "not_found", Title: "Title of not_found", Short: "Short of not_found",
+			Solutions: api.Solutions{"retry": api.Solution{Code: "retry", Short: "Retry"}},
+			Metadata:  api.ErrorMetadata{"tier": "critical"},
+		}
+
+		merged := render(t, &Options{Output: existing(t, content)}, specs)
+		assert.Equal(t, `---
+`+header+`
+name: app
+version: v1
+errors_definitions:
+    not_found:
+        code: not_found
+        short: Short of not_found
+        title: Title of not_found
+        solutions:
+            # orphaned: not defined by the source code anymore
+            removed:
+                code: removed
+                short: Removed
+            retry:
+                code: retry
+                short: Retry
+        metadata:
+            # orphaned: not defined by the source code anymore
+            owner: storage
+            tier: critical
+    timeout:
+        code: timeout
+        short: Short of timeout
+        title: Title of timeout
+        metadata:
+            # orphaned: not defined by the source code anymore
+            owner: network
+base_url: ""
+`, merged)
+
+		assert.Equal(t, `---
+`+header+`
+name: app
+version: v1
+errors_definitions:
+    not_found:
+        code: not_found
+        short: Short of not_found
+        title: Title of not_found
+        solutions:
+            retry:
+                code: retry
+                short: Retry
+        metadata:
+            tier: critical
+    timeout:
+        code: timeout
+        short: Short of timeout
+        title: Title of timeout
+base_url: ""
+`, render(t, &Options{Output: existing(t, merged), Orphans: OrphansDrop}, specs))
+	})
+	t.Run("Successfully drop the orphaned errors and applications", func(t *testing.T) {
+		t.Parallel()
+		output := existing(t, `---
+name: app
+version: v1
+errors_definitions:
+    removed:
+        code: removed
+        short: Removed
+        title: Removed
+---
+name: legacy
+version: v1
+`)
+		assert.Equal(t, `---
+`+header+`
+name: app
+version: v1
+errors_definitions:
+    kept:
+        code: kept
+        short: Short of kept
+        title: Title of kept
+base_url: ""
+`, render(t, &Options{Output: output, Orphans: OrphansDrop}, manifest("kept")))
+	})
+	t.Run("Fail to merge an invalid manifest", func(t *testing.T) {
+		t.Parallel()
+		output := existing(t, "- app\n")
+		_, err := New(&Options{Output: output, Merge: true}).Render(context.Background(), manifest())
+		assert.Error(t, err)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	specs := func() map[string]any {
+		return map[string]any{
+			"web": &api.Manifest{Name: "web", Version: "v1", ErrorsDefinitions: api.ErrorDefinitions{
+				"timeout":   {Code: "timeout", Title: "Timeout", Short: "Timeout."},
+				"not_found": {Code: "not_found", Title: "Not Found", Short: "Not found."},
+			}},
+			"cli": &api.Manifest{Name: "cli", Version: "v1"},
+		}
+	}
+
+	t.Run("Successfully render the applications as a stable multi-document stream", func(t *testing.T) {
+		t.Parallel()
+		files, err := New(&Options{Output: "errors.yaml", Header: header}).Render(context.Background(), specs())
+		require.NoError(t, err)
+		assert.Equal(t, `---
+`+header+`
+base_url: ""
+name: cli
+version: v1
+---
+`+header+`
+base_url: ""
+errors_definitions:
+    not_found:
+        code: not_found
+        short: Not found.
+        title: Not Found
+    timeout:
+        code: timeout
+        short: Timeout.
+        title: Timeout
+name: web
+version: v1
+`, string(files["errors.yaml"]))
+
+		for i := 0; i < 10; i++ {
+			again, err := New(&Options{Output: "errors.yaml", Header: header}).Render(context.Background(), specs())
+			require.NoError(t, err)
+			assert.Equal(t, files, again)
+		}
+	})
+	t.Run("Successfully render each application to its own file", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		generator := New(&Options{Output: filepath.Join(dir, "errors-{name}.yaml"), Header: header})
+		files, err := generator.Render(context.Background(), specs())
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Contains(t, string(files[filepath.Join(dir, "errors-cli.yaml")]), "name: cli\n")
+		assert.NotContains(t, string(files[filepath.Join(dir, "errors-cli.yaml")]), "name: web\n")
+		assert.Contains(t, string(files[filepath.Join(dir, "errors-web.yaml")]), "name: web\n")
+
+		legacy := specs()
+		legacy["legacy"] = &api.Manifest{Name: "legacy", Version: "v1"}
+		require.NoError(t, generator.Generate(context.Background(), legacy))
+		require.NoError(t, generator.Generate(context.Background(), specs()))
+		outputs, err := generator.Outputs()
+		require.NoError(t, err)
+		// the files of the applications which aren't generated anymore are removed
+		assert.Equal(t, []string{filepath.Join(dir, "errors-cli.yaml"), filepath.Join(dir, "errors-web.yaml")}, outputs)
+		_, err = os.Stat(filepath.Join(dir, "errors-legacy.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("Successfully keep the files of the output directory which weren't generated", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		unrelated := map[string]string{
+			filepath.Join(dir, "errors-handwritten.yaml"): "services: {}\n",
+			filepath.Join(dir, "docker-compose.yaml"):     "services: {}\n",
+		}
+		for path, content := range unrelated {
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+
+		for _, opts := range []*Options{
+			{Output: filepath.Join(dir, "errors-{name}.yaml")},
+			{Output: filepath.Join(dir, "{name}.yaml")},
+			{Output: filepath.Join(dir, "{name}.yaml"), Merge: true},
+		} {
+			generator := New(opts)
+			require.NoError(t, generator.Generate(context.Background(), specs()))
+			outputs, err := generator.Outputs()
+			require.NoError(t, err)
+			assert.Len(t, outputs, 2)
+			for path, content := range unrelated {
+				body, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, content, string(body))
+			}
+		}
+	})
+	t.Run("Fail to render an application outside of the output directory", func(t *testing.T) {
+		t.Parallel()
+		_, err := New(&Options{Output: filepath.Join(t.TempDir(), "errors-{name}.yaml")}).Render(context.Background(), map[string]any{
+			"../app": &api.Manifest{Name: "../app"},
+		})
+		assert.Error(t, err)
+	})
+	t.Run("Successfully mark the files of the orphaned applications", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		legacy := specs()
+		legacy["legacy"] = &api.Manifest{Name: "legacy", Version: "v1"}
+		require.NoError(t, New(&Options{Output: filepath.Join(dir, "errors-{name}.yaml")}).Generate(context.Background(), legacy))
+
+		files, err := New(&Options{Output: filepath.Join(dir, "errors-{name}.yaml"), Merge: true}).Render(context.Background(), specs())
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+		assert.Equal(t, "---\n\n# orphaned: not defined by the source code anymore\nbase_url: \"\"\nname: legacy\nversion: v1\n", string(files[filepath.Join(dir, "errors-legacy.yaml")]))
+	})
+}
@@ -0,0 +1,273 @@
+package grammar
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+const (
+	// beginKey and endKey delimit an explicit error block, i.e:
+	//
+	//	@fyi.error begin
+	//	@fyi.error title Not Found
+	//	@fyi.error code not_found
+	//	@fyi.error end
+	beginKey = "begin"
+	endKey   = "end"
+	codeKey  = "code"
+)
+
+// statementHint describes the statements, it's the hint of the diagnostics of malformed statements
+const statementHint = "the statements are written as @fyi[.error[.solution|.metadata]] <key> <value>, i.e: @fyi.error title Not Found"
+
+// blockHint describes the error blocks, it's the hint of the diagnostics of statements outside of an error block
+const blockHint = "an error starts at its @fyi.error code statement, or at @fyi.error begin until @fyi.error end, " +
+	"its solutions start at their @fyi.error.solution code statement"
+
+// evaluator evaluates the statements of a comment group into a partial specification.
+// The error statements are grouped in blocks, a block starts at each @fyi.error code statement, or explicitly at
+// @fyi.error begin until @fyi.error end. The solution statements belong to the error block they're in, a solution starts
+// at each @fyi.error.solution code statement. Statements outside of a block are reported and ignored, unless a default
+// code is given, then they start a block with the default code. Blocks without a code statement also take the default code.
+type evaluator struct {
+	spec        *api.Manifest
+	diagnostics diagnostic.List
+	// code is the code of the error statements outside of a block, i.e: the code of the fyi.Error call the comment
+	// documents. The statements outside of a block are ignored if it's empty.
+	code string
+
+	// current is the error of the current block, nil outside of a block
+	current *api.Error
+	// start is the position of the statement starting the current block
+	start lexer.Position
+	// explicit is set if the current block was started by @fyi.error begin
+	explicit bool
+	// solution is the current solution of the error block, nil until the first solution code statement
+	solution *api.Solution
+	// solutionStart is the position of the code statement starting the current solution
+	solutionStart lexer.Position
+}
+
+func newEvaluator(code string) *evaluator {
+	return &evaluator{
+		code: code,
+		spec: &api.Manifest{
+			BaseUrl:           "",
+			Description:       nil,
+			ErrorsDefinitions: api.ErrorDefinitions{},
+			Name:              "",
+			Title:             nil,
+			Version:           "",
+		},
+	}
+}
+
+// statement evaluates the statement into the specification, or the current error block
+func (e *evaluator) statement(attr *Statement) {
+	key := strings.ToLower(attr.Scope.Value)
+	if attr.Value == "" && (attr.Scope.GetType() != ".error" || (key != beginKey && key != endKey)) {
+		e.report(attr.Pos, diagnostic.Error, statementHint, "%s %s has no value, the statement is ignored", attr.Scope.Type, attr.Scope.Value)
+		return
+	}
+
+	switch attr.Scope.GetType() {
+	case "":
+		e.assign(attr, e.spec)
+	case ".error":
+		e.errorStatement(attr, key)
+	case ".error.solution":
+		e.solutionStatement(attr, key)
+	case ".error.metadata":
+		if e.outside(attr) {
+			return
+		}
+		// the key of metadata statements is free-form, i.e: @fyi.error.metadata owner team-payments
+		if e.current.Metadata == nil {
+			e.current.Metadata = api.ErrorMetadata{}
+		}
+		e.current.Metadata[attr.Scope.Value] = formatValue(attr.Value)
+	default:
+		e.report(attr.Pos, diagnostic.Warning, "the supported scopes are: @fyi, @fyi.error, @fyi.error.solution, @fyi.error.metadata",
+			"unsupported scope %s, the statement is ignored", attr.Scope.Type)
+	}
+}
+
+func (e *evaluator) errorStatement(attr *Statement, key string) {
+	switch key {
+	case beginKey:
+		if attr.Value != "" {
+			e.report(attr.Pos, diagnostic.Warning, "", "@fyi.error begin takes no value, the value is ignored")
+		}
+		if e.explicit {
+			e.report(attr.Pos, diagnostic.Warning, "close the error block with @fyi.error end before starting the next one",
+				"@fyi.error begin inside of the error block started at line %d, the block is closed", e.start.Line)
+		}
+		e.closeError()
+		e.open(attr.Pos, true)
+	case endKey:
+		if attr.Value != "" {
+			e.report(attr.Pos, diagnostic.Warning, "", "@fyi.error end takes no value, the value is ignored")
+		}
+		if !e.explicit {
+			e.report(attr.Pos, diagnostic.Warning, blockHint, "@fyi.error end without a matching @fyi.error begin, the statement is ignored")
+			return
+		}
+		e.closeError()
+	case codeKey:
+		if e.explicit && e.current.Code != "" {
+			e.report(attr.Pos, diagnostic.Error, "close the error block with @fyi.error end before defining the next error",
+				"the error block started at line %d already has the code %q, the statement is ignored", e.start.Line, e.current.Code)
+			return
+		}
+		if !e.explicit {
+			e.closeError()
+			e.open(attr.Pos, false)
+		}
+		e.assign(attr, e.current)
+	default:
+		if e.outside(attr) {
+			return
+		}
+		e.assign(attr, e.current)
+	}
+}
+
+func (e *evaluator) solutionStatement(attr *Statement, key string) {
+	if e.outside(attr) {
+		return
+	}
+	if key == codeKey {
+		e.closeSolution()
+		e.solution = &api.Solution{}
+		e.solutionStart = attr.Pos
+		e.assign(attr, e.solution)
+		return
+	}
+	if e.solution == nil {
+		e.report(attr.Pos, diagnostic.Warning, blockHint,
+			"%s %s before the solution code, the statement is ignored", attr.Scope.Type, attr.Scope.Value)
+		return
+	}
+	e.assign(attr, e.solution)
+}
+
+// outside reports the statement if it's outside of an error block. A block with the default code is started instead,
+// if there is one.
+func (e *evaluator) outside(attr *Statement) bool {
+	if e.current != nil {
+		return false
+	}
+	if e.code != "" {
+		e.open(attr.Pos, false)
+		e.current.Code = e.code
+		return false
+	}
+	e.report(attr.Pos, diagnostic.Warning, blockHint,
+		"%s %s outside of an error block, the statement is ignored", attr.Scope.Type, attr.Scope.Value)
+	return true
+}
+
+// open starts a new error block
+func (e *evaluator) open(start lexer.Position, explicit bool) {
+	e.current = &api.Error{
+		Code:      "",
+		Long:      nil,
+		Meta:      &api.ErrorMeta{Loc: nil},
+		Short:     "",
+		Title:     "",
+		Solutions: api.Solutions{},
+	}
+	e.start = start
+	e.explicit = explicit
+}
+
+// closeSolution adds the current solution to the error of the block
+func (e *evaluator) closeSolution() {
+	if e.solution == nil {
+		return
+	}
+	solution := e.solution
+	e.solution = nil
+	if solution.Code == "" {
+		return
+	}
+	if _, ok := e.current.Solutions[solution.Code]; ok {
+		e.report(e.solutionStart, diagnostic.Warning, "", "solution %q of error %q is defined twice, the last definition is used", solution.Code, e.current.Code)
+	}
+	if solution.Short == "" {
+		e.report(e.solutionStart, diagnostic.Warning, "", "solution %q of error %q has no short description", solution.Code, e.current.Code)
+	}
+	e.current.Solutions[solution.Code] = *solution
+}
+
+// closeError adds the error of the current block to the specification
+func (e *evaluator) closeError() {
+	if e.current == nil {
+		return
+	}
+	e.closeSolution()
+	definition := e.current
+	e.current, e.explicit = nil, false
+
+	if definition.Code == "" {
+		definition.Code = e.code
+	}
+	if definition.Code == "" {
+		e.report(e.start, diagnostic.Warning, "add a @fyi.error code statement to the error block", "error block without a code, the block is ignored")
+		return
+	}
+	if _, ok := e.spec.ErrorsDefinitions[definition.Code]; ok {
+		e.report(e.start, diagnostic.Warning, "", "error %q is defined twice in the comment, the last definition is used", definition.Code)
+	}
+	if definition.Title == "" {
+		e.report(e.start, diagnostic.Warning, "", "error %q has no title", definition.Code)
+	}
+	if definition.Short == "" {
+		e.report(e.start, diagnostic.Warning, "", "error %q has no short description", definition.Code)
+	}
+	e.spec.ErrorsDefinitions[definition.Code] = *definition
+}
+
+// finish closes the last error block and returns the specification and its diagnostics, sorted by position
+func (e *evaluator) finish() (*api.Manifest, diagnostic.List) {
+	if e.explicit {
+		e.report(e.start, diagnostic.Warning, "close the error block with @fyi.error end", "@fyi.error begin without a matching @fyi.error end")
+	}
+	e.closeError()
+	e.diagnostics.Sort()
+	return e.spec, e.diagnostics
+}
+
+// assign sets the field of the target struct the statement refers to, the unknown keys and invalid values are reported
+func (e *evaluator) assign(attr *Statement, target any) bool {
+	fields := reflect.VisibleFields(reflect.TypeOf(target).Elem())
+	pValue := reflect.ValueOf(target).Elem()
+	key := strings.ToLower(attr.Scope.Value)
+	found, err := parseAndAssignStructFields(key, formatValue(attr.Value), fields, pValue)
+	switch {
+	case !found:
+		e.report(attr.Pos, diagnostic.Warning,
+			fmt.Sprintf("the keys supported by %s are: %s", attr.Scope.Type, strings.Join(supportedKeys(fields), ", ")),
+			"unknown key %q in %s, the statement is ignored", attr.Scope.Value, attr.Scope.Type)
+		return false
+	case err != nil:
+		e.report(attr.Pos, diagnostic.Error, "", "invalid %s %s: %s", attr.Scope.Type, key, err)
+		return false
+	}
+	return true
+}
+
+func (e *evaluator) report(position lexer.Position, severity diagnostic.Severity, hint, format string, args ...any) {
+	e.diagnostics = append(e.diagnostics, diagnostic.Diagnostic{
+		Severity: severity,
+		Line:     position.Line,
+		Column:   position.Column,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
@@ -0,0 +1,108 @@
+package grammar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// Format returns the annotations describing the manifest, one statement per line. Evaluating the annotations with Eval
+// returns the same manifest. The values are written as they are when possible, or quoted otherwise.
+func Format(spec *api.Manifest) string {
+	var b strings.Builder
+	statement := func(scope, key, value string) {
+		fmt.Fprintf(&b, "@fyi%s %s %s\n", scope, key, formatLiteral(value))
+	}
+	optional := func(scope, key string, value *string) {
+		if value != nil {
+			statement(scope, key, *value)
+		}
+	}
+
+	if spec.Name != "" {
+		statement("", "name", spec.Name)
+	}
+	optional("", "title", spec.Title)
+	optional("", "description", spec.Description)
+	if spec.BaseUrl != "" {
+		statement("", "base_url", spec.BaseUrl)
+	}
+	if spec.Version != "" {
+		statement("", "version", spec.Version)
+	}
+
+	codes := make([]string, 0, len(spec.ErrorsDefinitions))
+	for code := range spec.ErrorsDefinitions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		definition := spec.ErrorsDefinitions[code]
+		statement(".error", "code", definition.Code)
+		statement(".error", "title", definition.Title)
+		statement(".error", "short", definition.Short)
+		optional(".error", "long", definition.Long)
+
+		keys := make([]string, 0, len(definition.Metadata))
+		for key := range definition.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			statement(".error.metadata", key, definition.Metadata[key])
+		}
+
+		solutions := make([]string, 0, len(definition.Solutions))
+		for code := range definition.Solutions {
+			solutions = append(solutions, code)
+		}
+		sort.Strings(solutions)
+		for _, code := range solutions {
+			solution := definition.Solutions[code]
+			statement(".error.solution", "code", solution.Code)
+			optional(".error.solution", "title", solution.Title)
+			statement(".error.solution", "short", solution.Short)
+			optional(".error.solution", "long", solution.Long)
+		}
+	}
+	return b.String()
+}
+
+// formatLiteral returns the value as it is, if Eval reads it back unchanged, or quoted
+func formatLiteral(value string) string {
+	if isLiteral(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// isLiteral checks if the value can be written without quotes, see formatValue
+func isLiteral(value string) bool {
+	if value == "" || value != strings.TrimSpace(value) || !utf8.ValidString(value) ||
+		strings.HasPrefix(value, `"`) || strings.Contains(value, "@fyi") {
+		return false
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+
+	lines := strings.Split(value, "\n")
+	dedented := len(lines) == 1
+	for i, line := range lines {
+		if line != strings.TrimRight(line, " \t") || strings.HasSuffix(line, `\`) {
+			return false
+		}
+		// the continuation lines are dedented by their common indentation, one of them mustn't be indented
+		if i > 0 && line != "" && line == strings.TrimLeft(line, " \t") {
+			dedented = true
+		}
+	}
+	return dedented
+}
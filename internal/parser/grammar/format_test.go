@@ -0,0 +1,83 @@
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func manifestWithValue(value string) *api.Manifest {
+	long := value
+	title := value
+	return &api.Manifest{
+		Name:        "app",
+		Description: &long,
+		BaseUrl:     "https://example.com",
+		Version:     "v1.0.0",
+		ErrorsDefinitions: api.ErrorDefinitions{
+			"error_code": {
+				Code:  "error_code",
+				Title: value,
+				Short: value,
+				Long:  &long,
+				Meta:  &api.ErrorMeta{},
+				Metadata: api.ErrorMetadata{
+					"owner":  value,
+					"ticket": "https://example.com/tickets/1",
+				},
+				Solutions: api.Solutions{
+					"solution_code": {Code: "solution_code", Title: &title, Short: value},
+				},
+			},
+		},
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{
+		"plain text",
+		"Can't connect? Check the <host> & port; then retry! #1 @user *bold* `code`",
+		"日本語のエラー, ошибка, خطأ 🚨",
+		"first line\nsecond line\n\n* a list\n  * nested",
+		"  leading and trailing whitespace  ",
+		`"quoted" value`,
+		"a value mentioning @fyi.error code",
+		"line ending with a backslash \\\nnext",
+		"tab\tand\x00control",
+		"\n  indented\n  lines",
+		"",
+	} {
+		value := value
+		t.Run("Successfully round trip "+value, func(t *testing.T) {
+			t.Parallel()
+			expected := manifestWithValue(value)
+			actual, err := Eval(Format(expected))
+			require.NoError(t, err, Format(expected))
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	t.Run("Successfully write literal values unquoted", func(t *testing.T) {
+		t.Parallel()
+		title := "Can't connect"
+		assert.Equal(t, "@fyi name app\n@fyi title Can't connect\n@fyi base_url https://example.com\n@fyi version v1\n",
+			Format(&api.Manifest{Name: "app", Title: &title, BaseUrl: "https://example.com", Version: "v1", ErrorsDefinitions: api.ErrorDefinitions{}}))
+	})
+}
+
+func FuzzFormat(f *testing.F) {
+	for _, seed := range []string{"plain text", "multi\nline", `"quoted"`, "@fyi", "🚨 <&>", " ", "\\", "a\\\nb"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		expected := manifestWithValue(value)
+		annotations := Format(expected)
+		actual, err := Eval(annotations)
+		require.NoError(t, err, annotations)
+		assert.Equal(t, expected, actual, annotations)
+	})
+}
@@ -1,7 +1,13 @@
 package grammar
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	participle "github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/diagnostic"
 	"github.com/tfadeyi/errors/pkg/api"
 	"reflect"
 	"strconv"
@@ -14,134 +20,221 @@ type (
 		// Stmts is a list of Sloth grammar Statements
 		Stmts []*Statement `@@*`
 	}
-	// Statement is any comment starting with @sloth keyword
+	// Statement is any comment starting with @sloth keyword.
+	// The value can span multiple lines, until the next statement, and start on the line following the scope.
+	// Statements without a value are reported by Diagnose, except the @fyi.error begin and end delimiters.
 	Statement struct {
+		Pos   lexer.Position
 		Scope Scope  `@@`
-		Value string `Whitespace* @(String (Whitespace|EOL)*)+`
+		Value string `(Whitespace|EOL)* @((String|Quoted) (Whitespace|EOL)*)*`
 	}
 	// Scope defines the statement scope, similar to a code function
 	Scope struct {
-		// Type is the specification struct a statement refers to
-		Type  string `(Fyi @((".error" (".solution" | ".metadata")? ))?)`
-		Value string `Whitespace* @("name"|"title"|"description"|"base_url"|"version"|"short"|"long"|"code")`
+		// Type is the specification struct a statement refers to, i.e: @fyi.error
+		Type string `@Fyi`
+		// Value is the key of the field the statement sets, i.e: title, or the metadata key. Unknown keys are reported by Diagnose.
+		Value string `Whitespace* @String`
 	}
 )
 
-// GetType returns the type of the statement scope
+// GetType returns the type of the statement scope, i.e: .error, or an empty string for the application scope
 func (k Scope) GetType() string {
-	return k.Type
+	return strings.TrimPrefix(k.Type, "@fyi")
 }
 
-func parseAndAssignStructFields(attr string, value string, fields []reflect.StructField, pValue reflect.Value) error {
+// parseAndAssignStructFields sets the field of the struct tagged with the attr yaml key to the value.
+// It returns false if none of the fields has the key, or an error if the value can't be converted to the field type.
+func parseAndAssignStructFields(attr string, value string, fields []reflect.StructField, pValue reflect.Value) (bool, error) {
 	for _, field := range fields {
 		tag, ok := field.Tag.Lookup("yaml")
-		if !ok {
-			return nil
+		if !ok || !isSupportedField(field) {
+			continue
 		}
 		key := strings.Split(tag, ",")[0]
-		if attr == key {
-			// set field value
-			v := pValue.FieldByName(field.Name)
-			if v.IsValid() {
-				if v.CanSet() {
-					switch v.Kind() {
-					case reflect.Pointer:
-						v.Set(reflect.ValueOf(&value))
-					case reflect.Bool:
-						b, err := strconv.ParseBool(value)
-						if err != nil {
-							return err
-						}
-						v.SetBool(b)
-					case reflect.Float64:
-						f, err := strconv.ParseFloat(value, 64)
-						if err != nil {
-							return err
-						}
-						v.SetFloat(f)
-					case reflect.Map:
-						// label or annotation
-						m := strings.Split(value, " ")
-						v.SetMapIndex(reflect.ValueOf(m[0]), reflect.ValueOf(m[1]))
-					default:
-						v.Set(reflect.ValueOf(value))
-					}
-				}
+		if attr != key {
+			continue
+		}
+		// set field value
+		v := pValue.FieldByName(field.Name)
+		if !v.IsValid() || !v.CanSet() {
+			return false, nil
+		}
+		switch v.Kind() {
+		case reflect.Pointer:
+			v.Set(reflect.ValueOf(&value))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return true, errors.Errorf("%q isn't a boolean", value)
+			}
+			v.SetBool(b)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return true, errors.Errorf("%q isn't a number", value)
+			}
+			v.SetFloat(f)
+		case reflect.Map:
+			// label or annotation
+			m := strings.SplitN(value, " ", 2)
+			if len(m) != 2 {
+				return true, errors.Errorf("%q isn't a key followed by a value", value)
 			}
+			v.SetMapIndex(reflect.ValueOf(m[0]), reflect.ValueOf(m[1]))
+		default:
+			v.Set(reflect.ValueOf(value))
 		}
+		return true, nil
 	}
-	return nil
+	return false, nil
 }
 
-func (g Grammar) parse() (*api.Manifest, error) {
-	var spec = &api.Manifest{
-		BaseUrl:           "",
-		Description:       nil,
-		ErrorsDefinitions: api.ErrorDefinitions{},
-		Name:              "",
-		Title:             nil,
-		Version:           "",
-	}
-	var foundErr = &api.Error{
-		Code:      "",
-		Long:      nil,
-		Meta:      &api.ErrorMeta{Loc: nil},
-		Short:     "",
-		Title:     "",
-		Solutions: api.Solutions{},
-	}
-	var foundSolution = &api.Solution{
-		Code:  "",
-		Short: "",
-		Long:  nil,
-		Title: nil,
+// isSupportedField checks if the annotations can set the struct field, nested structs are set by their own scope
+func isSupportedField(field reflect.StructField) bool {
+	switch field.Type.Kind() {
+	case reflect.Pointer:
+		return field.Type.Elem().Kind() == reflect.String
+	case reflect.String, reflect.Bool, reflect.Float64:
+		return true
+	case reflect.Map:
+		return field.Type.Key().Kind() == reflect.String && field.Type.Elem().Kind() == reflect.String
 	}
+	return false
+}
 
-	for _, attr := range g.Stmts {
-		switch attr.Scope.GetType() {
-		case ".error.solution":
-			fields := reflect.VisibleFields(reflect.TypeOf(*foundSolution))
-			pValue := reflect.ValueOf(foundSolution).Elem()
-			if err := parseAndAssignStructFields(strings.ToLower(attr.Scope.Value), strings.TrimSpace(attr.Value), fields, pValue); err != nil {
-				continue
-			}
-			foundErr.Solutions[foundSolution.Code] = *foundSolution
-		case ".error":
-			fields := reflect.VisibleFields(reflect.TypeOf(*foundErr))
-			pValue := reflect.ValueOf(foundErr).Elem()
-			if err := parseAndAssignStructFields(strings.ToLower(attr.Scope.Value), strings.TrimSpace(attr.Value), fields, pValue); err != nil {
-				continue
-			}
-			spec.ErrorsDefinitions[foundErr.Code] = *foundErr
-		default:
-			fields := reflect.VisibleFields(reflect.TypeOf(*spec))
-			pValue := reflect.ValueOf(spec).Elem()
-			if err := parseAndAssignStructFields(strings.ToLower(attr.Scope.Value), strings.TrimSpace(attr.Value), fields, pValue); err != nil {
-				continue
-			}
+// supportedKeys returns the keys the annotations can set in the struct fields, in declaration order
+func supportedKeys(fields []reflect.StructField) []string {
+	var keys []string
+	for _, field := range fields {
+		tag, ok := field.Tag.Lookup("yaml")
+		if !ok || !isSupportedField(field) {
+			continue
 		}
+		keys = append(keys, strings.Split(tag, ",")[0])
 	}
+	return keys
+}
 
-	return spec, nil
+// formatValue formats the raw value of a statement, which might span multiple lines.
+// The continuation lines are dedented by their common indentation and blank lines are kept as paragraph breaks, so
+// markdown lists and code spans are preserved. Lines ending with a backslash are joined to the following line.
+// A value made of a single quoted string is unquoted, interpreting its escape sequences.
+func formatValue(raw string) string {
+	if value, ok := unquote(strings.TrimSpace(raw)); ok {
+		return value
+	}
+
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.ReplaceAll(raw, "\r", "\n")
+	lines := strings.Split(strings.TrimSpace(raw), "\n")
+
+	indent := -1
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		lines[i] = line
+		if i == 0 || line == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	var value strings.Builder
+	joining := false
+	for i, line := range lines {
+		if i > 0 && line != "" {
+			line = line[indent:]
+		}
+		if joining {
+			line = strings.TrimLeft(line, " \t")
+		} else if i > 0 {
+			value.WriteString("\n")
+		}
+		joining = strings.HasSuffix(line, `\`) && i < len(lines)-1
+		if joining {
+			line = strings.TrimRight(strings.TrimSuffix(line, `\`), " \t") + " "
+		}
+		value.WriteString(line)
+	}
+	return value.String()
 }
 
-func createGrammar(filename, source string, options ...participle.ParseOption) (*Grammar, error) {
-	ast, err := participle.Build[Grammar](
-		participle.Lexer(lexerDefinition),
-	)
+// unquote returns the unquoted value if it's a single double-quoted string
+func unquote(value string) (string, bool) {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", false
+	}
+	unquoted, err := strconv.Unquote(value)
 	if err != nil {
-		return nil, err
+		return "", false
+	}
+	return unquoted, true
+}
+
+// parse evaluates the statements into a partial specification, code is the default error code, see evaluator
+func (g Grammar) parse(code string) (*api.Manifest, diagnostic.List) {
+	e := newEvaluator(code)
+	for _, attr := range g.Stmts {
+		e.statement(attr)
 	}
+	return e.finish()
+}
+
+// parser is the participle parser of the grammar, it's built once and is safe to use concurrently
+var parser = participle.MustBuild[Grammar](
+	participle.Lexer(lexerDefinition),
+)
+
+func createGrammar(filename, source string, options ...participle.ParseOption) (*Grammar, error) {
+	return parser.ParseString(filename, source, options...)
+}
 
-	return ast.ParseString(filename, source, options...)
+// Fingerprint returns a digest of the grammar and lexer rules, it changes whenever the annotations grammar changes
+func Fingerprint() string {
+	hash := sha256.New()
+	hash.Write([]byte(parser.String()))
+	for _, rule := range lexerRules {
+		fmt.Fprintf(hash, "\n%s=%s", rule.Name, rule.Pattern)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
 }
 
-// Eval evaluates the source input against the grammar and returns an instance of *sloth.spec
+// Eval evaluates the source input against the grammar and returns an instance of *sloth.spec.
+// An error is returned if the source can't be parsed or contains invalid statements, see Diagnose.
 func Eval(source string, options ...participle.ParseOption) (*api.Manifest, error) {
 	grammar, err := createGrammar("", source, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return grammar.parse()
+	spec, diagnostics := grammar.parse("")
+	for _, d := range diagnostics {
+		if d.Severity == diagnostic.Error {
+			return nil, errors.Errorf("%d:%d: %s", d.Line, d.Column, d.Message)
+		}
+	}
+	return spec, nil
+}
+
+// Diagnose evaluates the source input against the grammar, like Eval, and returns the problems found in the annotations.
+// The code, if not empty, is the code of the error statements without a code statement, i.e: the code of the fyi.Error
+// call the annotations document. The diagnostics are located relative to the source. The specification is nil if the
+// source can't be parsed.
+func Diagnose(source, code string) (*api.Manifest, diagnostic.List) {
+	grammar, err := createGrammar("", source)
+	if err != nil {
+		d := diagnostic.Diagnostic{
+			Severity: diagnostic.Error,
+			Message:  err.Error(),
+			Hint:     statementHint,
+		}
+		var perr participle.Error
+		if errors.As(err, &perr) {
+			d.Line, d.Column, d.Message = perr.Position().Line, perr.Position().Column, perr.Message()
+		}
+		d.Message = "invalid annotations, " + d.Message
+		return nil, diagnostic.List{d}
+	}
+	return grammar.parse(code)
 }
@@ -1,10 +1,13 @@
 package grammar
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
 )
 
 func TestGrammar(t *testing.T) {
@@ -123,4 +126,200 @@ func TestGrammar(t *testing.T) {
 		assert.EqualValues(t, "restart_machine", app.ErrorsDefinitions["validate_not_implemented"].Solutions["restart_machine"].Code)
 		assert.EqualValues(t, "Restart machine", app.ErrorsDefinitions["validate_not_implemented"].Solutions["restart_machine"].Short)
 	})
+	t.Run("Successfully parse multi-line values", func(t *testing.T) {
+		app, err := Eval(`@fyi.error code multi_line
+@fyi.error title Multi-line \
+  values
+@fyi.error short The value continues
+on the following line.
+@fyi.error long
+    The first paragraph, with a ` + "`code span`" + `.
+
+    * a markdown list
+      * nested item
+@fyi.error.solution code try_again
+@fyi.error.solution short Try again.`)
+		require.NoError(t, err)
+		require.Contains(t, app.ErrorsDefinitions, "multi_line")
+		definition := app.ErrorsDefinitions["multi_line"]
+		assert.EqualValues(t, "Multi-line values", definition.Title)
+		assert.EqualValues(t, "The value continues\non the following line.", definition.Short)
+		require.NotNil(t, definition.Long)
+		assert.EqualValues(t, "The first paragraph, with a `code span`.\n\n* a markdown list\n  * nested item", *definition.Long)
+		assert.EqualValues(t, "Try again.", definition.Solutions["try_again"].Short)
+	})
+	t.Run("Successfully parse error metadata", func(t *testing.T) {
+		app, err := Eval(`@fyi.error code not_found
+@fyi.error title Not Found
+@fyi.error short The resource wasn't found.
+@fyi.error.metadata owner team-storage
+@fyi.error.metadata Ticket https://example.com/tickets/1`)
+		require.NoError(t, err)
+		require.Contains(t, app.ErrorsDefinitions, "not_found")
+		assert.Equal(t, api.ErrorMetadata{
+			"owner":  "team-storage",
+			"Ticket": "https://example.com/tickets/1",
+		}, app.ErrorsDefinitions["not_found"].Metadata)
+	})
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully report unknown keys and incomplete errors", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose(`@fyi name cli
+@fyi colour blue
+@fyi.error code not_found
+@fyi.error titel Not Found
+@fyi.error.solution code try_again`, "")
+		require.NotNil(t, app)
+		assert.Equal(t, "cli", app.Name)
+		assert.Contains(t, app.ErrorsDefinitions, "not_found")
+
+		var found []string
+		for _, d := range diagnostics {
+			found = append(found, d.String())
+		}
+		assert.Equal(t, []string{
+			`warning: unknown key "colour" in @fyi, the statement is ignored`,
+			`warning: error "not_found" has no title`,
+			`warning: error "not_found" has no short description`,
+			`warning: unknown key "titel" in @fyi.error, the statement is ignored`,
+			`warning: solution "try_again" of error "not_found" has no short description`,
+		}, found)
+		assert.Equal(t, 2, diagnostics[0].Line)
+		assert.Equal(t, 1, diagnostics[0].Column)
+		assert.Equal(t, "the keys supported by @fyi are: base_url, description, name, title, version", diagnostics[0].Hint)
+		assert.Equal(t, 3, diagnostics[1].Line)
+	})
+	t.Run("Successfully report no diagnostics for valid annotations", func(t *testing.T) {
+		t.Parallel()
+		_, diagnostics := Diagnose(`@fyi.error code not_found
+@fyi.error title Not Found
+@fyi.error short The resource wasn't found.`, "")
+		assert.Empty(t, diagnostics)
+	})
+	t.Run("Fail to evaluate statements without a value", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose("@fyi.error code not_found\n@fyi.error title Not Found\n  @fyi.error short", "")
+		require.NotNil(t, app)
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, `warning: error "not_found" has no short description`, diagnostics[0].String())
+		assert.Equal(t, "error: @fyi.error short has no value, the statement is ignored", diagnostics[1].String())
+		assert.Equal(t, 3, diagnostics[1].Line)
+		assert.Equal(t, 3, diagnostics[1].Column)
+
+		_, err := Eval("@fyi.error code not_found\n@fyi.error title Not Found\n  @fyi.error short")
+		assert.EqualError(t, err, "3:3: @fyi.error short has no value, the statement is ignored")
+	})
+	t.Run("Fail to parse malformed statements", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose("@fyi.error code not_found\n@fyi.error \"title\" Not Found", "")
+		assert.Nil(t, app)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, diagnostic.Error, diagnostics[0].Severity)
+		assert.Equal(t, 2, diagnostics[0].Line)
+		assert.NotEmpty(t, diagnostics[0].Hint)
+	})
+}
+
+func TestErrorBlocks(t *testing.T) {
+	t.Parallel()
+
+	messages := func(diagnostics diagnostic.List) []string {
+		var found []string
+		for _, d := range diagnostics {
+			found = append(found, fmt.Sprintf("%d: %s", d.Line, d.Message))
+		}
+		return found
+	}
+
+	t.Run("Successfully start a new error at each code statement", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose(`@fyi.error code first
+@fyi.error title First
+@fyi.error short The first error.
+@fyi.error long Only the first error has a long description.
+@fyi.error.metadata owner team-first
+@fyi.error.solution code retry
+@fyi.error.solution short Retry.
+@fyi.error code second
+@fyi.error title Second
+@fyi.error short The second error.
+@fyi.error.solution code restart
+@fyi.error.solution short Restart.`, "")
+		assert.Empty(t, diagnostics)
+		require.Len(t, app.ErrorsDefinitions, 2)
+
+		first, second := app.ErrorsDefinitions["first"], app.ErrorsDefinitions["second"]
+		assert.Equal(t, "First", first.Title)
+		require.NotNil(t, first.Long)
+		assert.Equal(t, api.ErrorMetadata{"owner": "team-first"}, first.Metadata)
+		assert.Equal(t, api.Solutions{"retry": {Code: "retry", Short: "Retry."}}, first.Solutions)
+
+		assert.Equal(t, "Second", second.Title)
+		assert.Nil(t, second.Long, "the fields don't bleed into the following error")
+		assert.Nil(t, second.Metadata)
+		assert.Equal(t, api.Solutions{"restart": {Code: "restart", Short: "Restart."}}, second.Solutions)
+	})
+	t.Run("Successfully delimit an error with begin and end", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose(`@fyi.error begin
+@fyi.error title Not Found
+@fyi.error short The resource wasn't found.
+@fyi.error code not_found
+@fyi.error.solution code check
+@fyi.error.solution short Check the name.
+@fyi.error end
+@fyi.error begin
+@fyi.error code conflict
+@fyi.error title Conflict
+@fyi.error short The resource already exists.
+@fyi.error end`, "")
+		assert.Empty(t, diagnostics)
+		require.Len(t, app.ErrorsDefinitions, 2)
+		assert.Equal(t, "Not Found", app.ErrorsDefinitions["not_found"].Title)
+		assert.Contains(t, app.ErrorsDefinitions["not_found"].Solutions, "check")
+		assert.Empty(t, app.ErrorsDefinitions["conflict"].Solutions)
+	})
+	t.Run("Successfully take the default code of the statements outside of a block", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose(`@fyi.error title Not Found
+@fyi.error short The resource wasn't found.
+@fyi.error code conflict
+@fyi.error title Conflict
+@fyi.error short The resource already exists.`, "not_found")
+		assert.Empty(t, diagnostics)
+		require.Len(t, app.ErrorsDefinitions, 2)
+		assert.Equal(t, "not_found", app.ErrorsDefinitions["not_found"].Code)
+		assert.Equal(t, "Not Found", app.ErrorsDefinitions["not_found"].Title)
+		assert.Equal(t, "Conflict", app.ErrorsDefinitions["conflict"].Title)
+	})
+	t.Run("Fail to evaluate ambiguous statements", func(t *testing.T) {
+		t.Parallel()
+		app, diagnostics := Diagnose(`@fyi.error title Orphan
+@fyi.error.solution code orphan
+@fyi.error code first
+@fyi.error title First
+@fyi.error short The first error.
+@fyi.error.solution short Before the code.
+@fyi.error end
+@fyi.error begin
+@fyi.error code second
+@fyi.error code third
+@fyi.error title Second
+@fyi.error short The second error.`, "")
+		assert.Equal(t, []string{
+			"1: @fyi.error title outside of an error block, the statement is ignored",
+			"2: @fyi.error.solution code outside of an error block, the statement is ignored",
+			"6: @fyi.error.solution short before the solution code, the statement is ignored",
+			"7: @fyi.error end without a matching @fyi.error begin, the statement is ignored",
+			"8: @fyi.error begin without a matching @fyi.error end",
+			`10: the error block started at line 8 already has the code "second", the statement is ignored`,
+		}, messages(diagnostics))
+		require.Len(t, app.ErrorsDefinitions, 2)
+		assert.Empty(t, app.ErrorsDefinitions["first"].Solutions)
+		assert.Equal(t, "Second", app.ErrorsDefinitions["second"].Title)
+	})
 }
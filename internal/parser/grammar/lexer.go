@@ -2,9 +2,15 @@ package grammar
 
 import "github.com/alecthomas/participle/v2/lexer"
 
-var lexerDefinition = lexer.MustSimple([]lexer.SimpleRule{
+// lexerRules are the rules of the annotations lexer, they are matched in order.
+// The statements start with the @fyi keyword and its scope, i.e: @fyi.error.solution. Any printable unicode text is
+// accepted as a value, quoted strings allow the values to contain escape sequences and @fyi keywords.
+var lexerRules = []lexer.SimpleRule{
 	{"EOL", `[\n\r]+`},
-	{"Fyi", `@fyi`},
-	{"String", `([a-zA-Z_0-9\.\/:,\-\'\(\)~\[\]\{\}=\"\|%])\w*`},
-	{"Whitespace", `[ \t]+`},
-})
+	{"Fyi", `@fyi(\.error(\.solution|\.metadata)?)?\b`},
+	{"Quoted", `"(\\.|[^"\\\n\r])*"`},
+	{"String", `[^\s\p{Cc}]+`},
+	{"Whitespace", `[ \t\f]+`},
+}
+
+var lexerDefinition = lexer.MustSimple(lexerRules)
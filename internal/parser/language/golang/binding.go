@@ -0,0 +1,217 @@
+package golang
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+)
+
+// referencePrefix prefixes the placeholder code of the errors documenting a call whose code is a constant declared in
+// another file of the package, the placeholder is resolved once all the files of the package are loaded
+const referencePrefix = "\x00const:"
+
+// binding is the go code an annotated comment group documents
+type binding struct {
+	// node is the statement or declaration following the comment group, nil if the comment documents nothing
+	node ast.Node
+	// call is the fyi.Error or fyi.ErrorWithContext call of the documented code, nil if there is none
+	call *ast.CallExpr
+	// code is the code argument of the call, if it's a string literal or a constant declared in the file
+	code string
+	// reference is the name of the constant the code argument of the call refers to, if it's declared in another file
+	reference string
+	// constant is the name of the constant declaring the code, if the comment documents a constant declaration or
+	// the code argument of the call is a constant
+	constant string
+}
+
+// bind finds the go code documented by the comment group: the statement following it in the same block, or the
+// declaration following it at the top level, and the code of its error. The code of a constant declaration is the value
+// of the documented constant, i.e:
+//
+//	// @fyi.error title Not Found
+//	const ErrNotFound = "not_found"
+//
+// Otherwise it's the code of the fyi.Error call of the documented code. The call of a statement is its first fyi.Error
+// call, a declaration is only bound to a call if it contains exactly one, i.e: a function wrapping a single error.
+// The calls of Wrapper methods aren't bound, they can't be told apart from other methods without type checking.
+func bind(file *ast.File, group *ast.CommentGroup, library string, constants map[string]string) binding {
+	b := binding{node: documented(file, group)}
+	if gen := constDecl(b.node); gen != nil {
+		if spec := documentedSpec(gen, group); spec != nil && len(spec.Names) == 1 && len(spec.Values) == 1 {
+			if code, ok := stringLiteral(spec.Values[0]); ok {
+				b.code, b.constant = code, spec.Names[0].Name
+			}
+		}
+		return b
+	}
+	if b.node == nil || library == "" {
+		return b
+	}
+
+	var calls []*ast.CallExpr
+	ast.Inspect(b.node, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if _, isLibrary := codeArgument(call, library); isLibrary {
+				calls = append(calls, call)
+			}
+		}
+		return true
+	})
+	if _, isDecl := b.node.(ast.Decl); len(calls) == 0 || (isDecl && len(calls) > 1) {
+		return b
+	}
+
+	b.call = calls[0]
+	arg, _ := codeArgument(b.call, library)
+	if code, ok := stringLiteral(arg); ok {
+		b.code = code
+		return b
+	}
+	if ident, ok := unparen(arg).(*ast.Ident); ok {
+		if code, ok := constants[ident.Name]; ok {
+			b.code, b.constant = code, ident.Name
+		} else {
+			b.reference = ident.Name
+		}
+	}
+	return b
+}
+
+// symbol returns the qualified name of the constant declaring the code, i.e: store.ErrNotFound, or an empty string
+func (b binding) symbol(pkg string) string {
+	name := b.constant
+	if b.reference != "" {
+		name = b.reference
+	}
+	if name == "" {
+		return ""
+	}
+	return pkg + "." + name
+}
+
+// constDecl returns the constant declaration of the node, the node is either a top level declaration or a declaration
+// statement. It returns nil for other nodes.
+func constDecl(node ast.Node) *ast.GenDecl {
+	if stmt, ok := node.(*ast.DeclStmt); ok {
+		node = stmt.Decl
+	}
+	if gen, ok := node.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+		return gen
+	}
+	return nil
+}
+
+// documentedSpec returns the constant specification documented by the comment group: the only specification of the
+// declaration it documents, or the specification it documents inside of a grouped declaration
+func documentedSpec(gen *ast.GenDecl, group *ast.CommentGroup) *ast.ValueSpec {
+	if group.End() <= gen.TokPos || !gen.Lparen.IsValid() || group.End() <= gen.Lparen {
+		if len(gen.Specs) != 1 {
+			return nil
+		}
+		spec, _ := gen.Specs[0].(*ast.ValueSpec)
+		return spec
+	}
+	for _, s := range gen.Specs {
+		spec, ok := s.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		if spec.Comment == group || spec.End() >= group.End() {
+			return spec
+		}
+	}
+	return nil
+}
+
+// defaultCode returns the code of the error statements outside of a block in the comment group, the code of the
+// bound call or the placeholder of the constant it refers to
+func (b binding) defaultCode() string {
+	if b.reference != "" {
+		return referencePrefix + b.reference
+	}
+	return b.code
+}
+
+// documented returns the statement following the comment group in the innermost block containing it, or the declaration
+// following it at the top level of the file. A statement or declaration containing the comment group is documented by it.
+func documented(file *ast.File, group *ast.CommentGroup) ast.Node {
+	var list []ast.Stmt
+	inBlock := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || node.Pos() > group.Pos() || node.End() < group.End() {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			list, inBlock = n.List, true
+		case *ast.CaseClause:
+			list, inBlock = n.Body, true
+		case *ast.CommClause:
+			list, inBlock = n.Body, true
+		}
+		return true
+	})
+
+	if !inBlock {
+		for _, decl := range file.Decls {
+			if decl.End() >= group.End() {
+				return decl
+			}
+		}
+		return nil
+	}
+	for _, stmt := range list {
+		switch stmt.(type) {
+		case *ast.CaseClause, *ast.CommClause:
+			// the comment is between the clauses of a switch or select
+			return nil
+		}
+		if stmt.End() >= group.End() {
+			return stmt
+		}
+	}
+	return nil
+}
+
+// fileConstants returns the string constants declared at the top level of the file, keyed by name
+func fileConstants(file *ast.File) map[string]string {
+	constants := map[string]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok || len(value.Values) != len(value.Names) {
+				continue
+			}
+			for i, name := range value.Names {
+				if code, ok := stringLiteral(value.Values[i]); ok {
+					constants[name.Name] = code
+				}
+			}
+		}
+	}
+	return constants
+}
+
+// loadConstants parses the string constants declared at the top level of the go file
+func loadConstants(filename string) (map[string]string, error) {
+	file, err := goparser.ParseFile(token.NewFileSet(), filename, nil, goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	return fileConstants(file), nil
+}
+
+// isReference checks if the error code is the placeholder of a constant declared in another file of the package,
+// and returns the name of the constant
+func isReference(code string) (string, bool) {
+	if !strings.HasPrefix(code, referencePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(code, referencePrefix), true
+}
@@ -0,0 +1,251 @@
+package golang
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestParserBinding(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\n// @fyi name app\n",
+		"store/codes.go": `package store
+
+const ErrConflict = "conflict"
+`,
+		"store/store.go": `package store
+
+import (
+	"errors"
+
+	fyi "github.com/tfadeyi/errors"
+)
+
+const errNotFound = "not_found"
+
+func Get(ok bool) error {
+	if !ok {
+		// @fyi.error title Not Found
+		// @fyi.error short The item was not found.
+		return fyi.Error(errors.New("get"), errNotFound)
+	}
+	// @fyi.error title Conflict
+	// @fyi.error short The item was modified concurrently.
+	return fyi.Error(errors.New("get"), ErrConflict)
+}
+
+// @fyi.error title Denied
+// @fyi.error short The item can't be deleted.
+func Delete() error {
+	return fyi.Error(errors.New("delete"), "denied")
+}
+
+func Put() error {
+	// @fyi.error code put_failed
+	// @fyi.error title Put
+	// @fyi.error short The item can't be stored.
+	return fyi.Error(errors.New("put"), "timeout")
+}
+
+func List() error {
+	// @fyi.error title Unknown
+	// @fyi.error short The code of the call is a variable.
+	code := "unknown"
+	return fyi.Error(errors.New("list"), code)
+}
+`,
+	})
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+	specs, err := p.Parse(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, specs, "app")
+	definitions := specs["app"].(*api.Manifest).ErrorsDefinitions
+
+	t.Run("Successfully take the code of the documented call", func(t *testing.T) {
+		t.Parallel()
+		for code, title := range map[string]string{
+			"not_found":  "Not Found",
+			"conflict":   "Conflict",
+			"denied":     "Denied",
+			"put_failed": "Put",
+		} {
+			require.Contains(t, definitions, code)
+			assert.Equal(t, code, definitions[code].Code)
+			assert.Equal(t, title, definitions[code].Title)
+		}
+		assert.Len(t, definitions, 4)
+	})
+
+	t.Run("Successfully report the annotations not matching the documented call", func(t *testing.T) {
+		t.Parallel()
+		var found []string
+		for _, d := range p.Diagnostics() {
+			rel, err := filepath.Rel(root, d.Filename)
+			require.NoError(t, err)
+			found = append(found, fmt.Sprintf("%s:%d:%d: %s", rel, d.Line, d.Column, d.Message))
+		}
+		assert.Equal(t, []string{
+			`store/store.go:29:2: the annotations document the call at line 32 wrapping the code "timeout", but don't define it`,
+			`store/store.go:36:5: @fyi.error title outside of an error block, the statement is ignored`,
+			`store/store.go:37:5: @fyi.error short outside of an error block, the statement is ignored`,
+		}, found)
+	})
+}
+
+func TestParserOwnership(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	owned := func(t *testing.T, specs map[string]any) map[string][]string {
+		result := map[string][]string{}
+		for name, spec := range specs {
+			codes := []string{}
+			for code := range spec.(*api.Manifest).ErrorsDefinitions {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			result[name] = codes
+		}
+		return result
+	}
+
+	t.Run("Successfully assign the errors to the application of their package", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{
+			"go.mod": "module example.com/app\n",
+			// the packages sorted before the applications are still owned by them
+			"a/a.go":                errorAnnotation("a", "a_code"),
+			"cli/main.go":           "package main\n\n// @fyi name cli\n",
+			"cli/flags/flags.go":    errorAnnotation("flags", "flags_code"),
+			"svc/server/main.go":    "package main\n\n// @fyi name server\n",
+			"svc/server/api/api.go": errorAnnotation("api", "api_code"),
+			"svc/server/server.go":  errorAnnotation("main", "server_code"),
+			// the errors annotated alongside the name of another application belong to it
+			"svc/server/other.go": "package main\n\n// @fyi name cli\n// @fyi.error code named_code\n// @fyi.error title T\n// @fyi.error short S.\n",
+			// the packages outside of the applications belong to the closest one
+			"svc/worker/worker.go": errorAnnotation("worker", "worker_code"),
+		})
+
+		specs, err := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}}).Parse(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"cli":    {"a_code", "flags_code", "named_code"},
+			"server": {"api_code", "server_code", "worker_code"},
+		}, owned(t, specs))
+	})
+
+	t.Run("Successfully skip the errors without an application", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{
+			"go.mod": "module example.com/app\n",
+			"a/a.go": errorAnnotation("a", "a_code"),
+		})
+
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+		specs, err := p.Parse(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, specs)
+		require.Len(t, p.Diagnostics(), 1)
+		assert.Equal(t, `no application owns the error "a_code", the error is skipped`, p.Diagnostics()[0].Message)
+	})
+}
+
+func TestParserConstants(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\n// @fyi name app\n",
+		"store/codes.go": `package store
+
+// @fyi.error title Not Found
+// @fyi.error short The item was not found.
+const ErrNotFound = "not_found"
+
+const (
+	// @fyi.error title Conflict
+	// @fyi.error short The item was modified concurrently.
+	ErrConflict = "conflict"
+
+	// @fyi.error title Denied
+	// @fyi.error short The item can't be deleted.
+	errDenied Code = "denied"
+)
+
+type Code string
+
+// @fyi.error title Ambiguous
+// @fyi.error short The comment documents several constants.
+const (
+	ErrFirst  = "first"
+	ErrSecond = "second"
+)
+`,
+		"store/store.go": `package store
+
+import fyi "github.com/tfadeyi/errors"
+
+func Get(err error) error {
+	// @fyi.error title Timeout
+	// @fyi.error short The store didn't answer in time.
+	return fyi.Error(err, ErrTimeout)
+}
+`,
+		"store/timeout.go": "package store\n\nconst ErrTimeout = \"timeout\"\n",
+	})
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+	specs, err := p.Parse(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, specs, "app")
+	definitions := specs["app"].(*api.Manifest).ErrorsDefinitions
+
+	t.Run("Successfully take the code and symbol of the documented constant", func(t *testing.T) {
+		t.Parallel()
+		for code, symbol := range map[string]string{
+			"not_found": "store.ErrNotFound",
+			"conflict":  "store.ErrConflict",
+			"denied":    "store.errDenied",
+			"timeout":   "store.ErrTimeout",
+		} {
+			require.Contains(t, definitions, code)
+			assert.Equal(t, code, definitions[code].Code)
+			require.NotNil(t, definitions[code].Meta, code)
+			require.NotNil(t, definitions[code].Meta.Symbol, code)
+			assert.Equal(t, symbol, *definitions[code].Meta.Symbol, code)
+		}
+		assert.Len(t, definitions, 4)
+	})
+
+	t.Run("Successfully report the comments documenting several constants", func(t *testing.T) {
+		t.Parallel()
+		var found []string
+		for _, d := range p.Diagnostics() {
+			found = append(found, fmt.Sprintf("%s:%d: %s", filepath.Base(d.Filename), d.Line, d.Message))
+		}
+		assert.Equal(t, []string{
+			`codes.go:19: @fyi.error title outside of an error block, the statement is ignored`,
+			`codes.go:20: @fyi.error short outside of an error block, the statement is ignored`,
+		}, found)
+	})
+}
@@ -0,0 +1,430 @@
+package golang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/cache"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/migrate"
+	"github.com/tfadeyi/errors/internal/parser/grammar"
+	"github.com/tfadeyi/errors/internal/version"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// cacheVersion is the version of the partial specifications evaluated from the annotations, it has to be bumped whenever
+// the evaluation changes so the previously cached annotations are invalidated
+const cacheVersion = "9"
+
+// sourceFile is a go file loaded by the parser
+type sourceFile struct {
+	// file is the parsed go file. Only its comments are read, unless the source code is analyzed.
+	file *ast.File
+	// annotations are the partial specifications of the annotated comment groups of the file, in source order
+	annotations []*api.Manifest
+	// diagnostics are the problems found in the file, they are located in the file by Diagnostics
+	diagnostics diagnostic.List
+	// failed is set if the file couldn't be parsed, Update keeps the previous annotations of the file
+	failed bool
+	// constants are the string constants declared at the top level of the file, they're loaded on demand to resolve
+	// the codes of the annotations documenting calls with a constant code declared in another file
+	constants map[string]string
+}
+
+// loadFiles reads the given go files with a bounded pool of workers and evaluates their annotations.
+// Files that can't be read are skipped with a warning, so are the files excluded by their build constraints without
+// warning, the syntax errors of the others are reported as diagnostics.
+func (p *Parser) loadFiles(ctx context.Context, fset *token.FileSet, filenames []string) (map[string]*sourceFile, error) {
+	workers := p.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(filenames) {
+		workers = len(filenames)
+	}
+
+	type result struct {
+		filename string
+		file     *sourceFile
+	}
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for filename := range jobs {
+				file, err := p.loadFile(fset, filename)
+				if err != nil {
+					p.warn(err, "file", filename)
+					file = nil
+				}
+				results <- result{filename: filename, file: file}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, filename := range filenames {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- filename:
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// the files are collected in a map, the order they are loaded in doesn't affect the merged specifications
+	files := make(map[string]*sourceFile, len(filenames))
+	for r := range results {
+		if r.file != nil {
+			files[r.filename] = r.file
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, errors.New("termination signal was received, terminating process...")
+	}
+	return files, nil
+}
+
+// loadFile reads the go file and evaluates its annotations, unless they are found in the cache.
+// No file is returned if the build constraints of the file exclude it.
+// Files without annotations are skipped altogether, unless the source code is analyzed. The whole file is only parsed if
+// the source code is analyzed or its annotations define errors, otherwise only its comments are scanned.
+func (p *Parser) loadFile(fset *token.FileSet, filename string) (*sourceFile, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !p.filter.matchSource(filename, src) {
+		return nil, nil
+	}
+
+	key, hash := cacheKey(filename), ""
+	if p.cache != nil {
+		hash = cache.Hash(src)
+		if entry, ok := p.cache.Get(key, int64(len(src)), hash); ok {
+			loaded := &sourceFile{file: &ast.File{}, annotations: entry.Annotations, diagnostics: entry.Diagnostics}
+			if p.analyze {
+				// the cached files were parsed successfully
+				if loaded.file, err = goparser.ParseFile(fset, filename, src, goparser.ParseComments); err != nil {
+					return nil, err
+				}
+			}
+			return loaded, nil
+		}
+	}
+
+	loaded := &sourceFile{file: &ast.File{}}
+	if p.analyze || p.hasAnnotations(src) {
+		file, scanned := (*ast.File)(nil), false
+		if !p.analyze && !p.hasErrorAnnotations(src) {
+			// the errors are bound to the go code documented by their annotations, the other annotations only need the
+			// comments of the file
+			file, err = scanComments(fset, filename, src)
+			scanned = err == nil
+		}
+		if !scanned {
+			mode := goparser.ParseComments
+			if !p.analyze {
+				mode |= goparser.SkipObjectResolution
+			}
+			if file, err = goparser.ParseFile(fset, filename, src, mode); err != nil {
+				// the annotations of files with syntax errors are skipped, the errors are reported as diagnostics
+				return &sourceFile{file: &ast.File{}, diagnostics: syntaxErrors(err), failed: true}, nil
+			}
+			loaded.constants = fileConstants(file)
+		}
+		loaded.file = file
+		loaded.annotations, loaded.diagnostics = p.evalAnnotations(fset, filename, file)
+	}
+	if p.cache != nil {
+		p.cache.Put(key, &cache.Entry{Size: int64(len(src)), Hash: hash, Annotations: loaded.annotations, Diagnostics: loaded.diagnostics})
+	}
+	return loaded, nil
+}
+
+// syntaxErrors returns the diagnostics of the go syntax errors of a file
+func syntaxErrors(err error) diagnostic.List {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return diagnostic.List{{Severity: diagnostic.Error, Message: err.Error()}}
+	}
+	var diagnostics diagnostic.List
+	for _, e := range list {
+		diagnostics = append(diagnostics, diagnostic.Diagnostic{
+			Severity: diagnostic.Error,
+			Line:     e.Pos.Line,
+			Column:   e.Pos.Column,
+			Message:  "the file can't be parsed, its annotations are skipped: " + e.Msg,
+		})
+	}
+	return diagnostics
+}
+
+// openCache opens the parse cache of the included directories, it's invalidated if the tool version, the grammar, the
+// evaluation of the annotations or the legacy annotation prefixes changed since it was written
+func (p *Parser) openCache() {
+	p.cache = nil
+	if p.cacheDir == "" {
+		return
+	}
+
+	key := cache.Key(cacheVersion, version.Version, version.Commit, grammar.Fingerprint(), strings.Join(p.annotationPrefixes, ","))
+	c, err := cache.Open(cache.Filename(p.cacheDir, p.includedDirs...), key)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+	case errors.Is(err, cache.ErrInvalidated):
+		p.logger.Debug("Discarding the outdated parse cache")
+	default:
+		p.warn(err)
+	}
+	p.cache = c
+}
+
+// saveCache writes the parse cache to disk, failing to write it only costs the next run a full parse
+func (p *Parser) saveCache() {
+	if p.cache == nil {
+		return
+	}
+	if err := p.cache.Save(); err != nil {
+		p.warn(errors.Annotate(err, "failed to write the parse cache"))
+	}
+}
+
+// cacheKey returns the key of the file in the cache, the absolute path
+func cacheKey(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		return abs
+	}
+	return filename
+}
+
+// hasAnnotations checks if the source might contain annotations, either in the current grammar or a legacy one
+func (p *Parser) hasAnnotations(src []byte) bool {
+	if bytes.Contains(src, []byte(migrate.Prefix)) {
+		return true
+	}
+	for _, prefix := range p.annotationPrefixes {
+		if bytes.Contains(src, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasErrorAnnotations checks if the source might contain error annotations. The legacy annotations are assigned to the
+// error scope by their code key, so any legacy annotation might be one.
+func (p *Parser) hasErrorAnnotations(src []byte) bool {
+	if bytes.Contains(src, []byte(migrate.Prefix+".error")) {
+		return true
+	}
+	for _, prefix := range p.annotationPrefixes {
+		if prefix != migrate.Prefix && bytes.Contains(src, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// evalAnnotations evaluates the annotated comment groups of the file into partial specifications, the errors are located
+// at their comment group. Each comment group is bound to the go code it documents, the error statements outside of a
+// block take the code of the documented fyi.Error call. The problems found in the annotations are returned as
+// diagnostics, located in the file. It doesn't depend on the parser state, so it's safe to call concurrently.
+func (p *Parser) evalAnnotations(fset *token.FileSet, filename string, file *ast.File) ([]*api.Manifest, diagnostic.List) {
+	var annotations []*api.Manifest
+	var diagnostics diagnostic.List
+	library, constants := libraryName(file), fileConstants(file)
+	for _, comment := range file.Comments {
+		text := strings.TrimSpace(commentText(comment))
+		if len(p.annotationPrefixes) > 0 {
+			// rewrite the legacy annotations into the current grammar
+			text, _ = migrate.RewriteComment(text, p.annotationPrefixes...)
+		}
+		if !strings.HasPrefix(text, migrate.Prefix) {
+			continue
+		}
+
+		p.logger.Debug("Parsing", "comment", text)
+		// partialServiceSpec contains the partially parsed sloth Specification for a given comment group
+		// this means the parsed spec will only contain data for the fields that are present in the comments, making the spec only partially accurate
+		bound := bind(file, comment, library, constants)
+		partialServiceSpec, found := grammar.Diagnose(text, bound.defaultCode())
+		for _, d := range found {
+			position := locate(fset, comment, text, d.Line, d.Column)
+			d.Line, d.Column = position.Line, position.Column
+			if bound.reference != "" {
+				// the placeholder code is replaced by the name of the constant it refers to
+				d.Message = strings.ReplaceAll(d.Message, strconv.Quote(bound.defaultCode()), bound.reference)
+			}
+			diagnostics = append(diagnostics, d)
+		}
+		if partialServiceSpec == nil {
+			continue
+		}
+
+		position := fset.Position(comment.Pos())
+		if _, ok := partialServiceSpec.ErrorsDefinitions[bound.code]; bound.code != "" && !ok && len(partialServiceSpec.ErrorsDefinitions) > 0 {
+			d := diagnostic.Diagnostic{
+				Severity: diagnostic.Warning,
+				Line:     position.Line,
+				Column:   position.Column,
+				Message:  fmt.Sprintf("the annotations document the constant %s = %q, but don't define its code", bound.constant, bound.code),
+				Hint:     "define the code of the constant, or move the annotations above the constant declaring their code",
+			}
+			if bound.call != nil {
+				call := fset.Position(bound.call.Pos())
+				d.Message = fmt.Sprintf("the annotations document the call at line %d wrapping the code %q, but don't define it", call.Line, bound.code)
+				d.Hint = "define the code of the call, or move the annotations next to the call wrapping their errors"
+			}
+			diagnostics = append(diagnostics, d)
+		}
+		function := enclosingFunction(file, comment.Pos())
+		symbol := bound.symbol(file.Name.Name)
+		for key, definition := range partialServiceSpec.ErrorsDefinitions {
+			line, column := position.Line, position.Column
+			definition.Meta = &api.ErrorMeta{
+				Loc: &api.ErrorMetaLoc{Path: filename, Line: &line, Column: &column},
+			}
+			if function != "" {
+				name := function
+				definition.Meta.Function = &name
+			}
+			if symbol != "" && key == bound.defaultCode() {
+				// the errors taking the code of the constant refer to it
+				name := symbol
+				definition.Meta.Symbol = &name
+			}
+			partialServiceSpec.ErrorsDefinitions[key] = definition
+		}
+		annotations = append(annotations, partialServiceSpec)
+	}
+	return annotations, diagnostics
+}
+
+// scanComments returns a go file only containing the package name and the comments of the source, grouped like
+// go/parser groups them. Scanning the comments is considerably cheaper than parsing the whole file.
+func scanComments(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	file := fset.AddFile(filename, -1, len(src))
+
+	var errs scanner.ErrorList
+	var s scanner.Scanner
+	s.Init(file, src, errs.Add, scanner.ScanComments)
+
+	var (
+		name   = ast.NewIdent("")
+		groups []*ast.CommentGroup
+		group  []*ast.Comment
+		// groupEnd is the line the current comment group ends at
+		groupEnd int
+		// lineComment is set if the current group started on the line of the previous token
+		lineComment bool
+		// tokenLine is the line of the last token which wasn't a comment
+		tokenLine = -1
+		// previous is the last token which wasn't a comment
+		previous token.Token
+	)
+	flush := func() {
+		if len(group) > 0 {
+			groups = append(groups, &ast.CommentGroup{List: group})
+		}
+		group = nil
+	}
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT {
+			// automatic semicolons are inserted at the position of the newline, or of a trailing comment
+			if tok != token.SEMICOLON || lit != "\n" {
+				flush()
+				tokenLine = file.Line(pos)
+			}
+			if previous == token.PACKAGE && tok == token.IDENT {
+				name = &ast.Ident{NamePos: pos, Name: lit}
+			}
+			previous = tok
+			continue
+		}
+
+		line := file.Line(pos)
+		switch {
+		case len(group) == 0:
+		case lineComment && line != groupEnd, !lineComment && line > groupEnd+1:
+			flush()
+		}
+		if len(group) == 0 {
+			lineComment = line == tokenLine
+		}
+		group = append(group, &ast.Comment{Slash: pos, Text: lit})
+		groupEnd = file.Line(pos + token.Pos(len(lit)))
+	}
+	flush()
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return &ast.File{Name: name, Comments: groups}, nil
+}
+
+// commentText returns the text of the comment group, like ast.CommentGroup.Text, also removing the leading asterisks
+// decorating the lines of block comments, i.e:
+//
+//	/*
+//	 * @fyi.error code example
+//	 */
+func commentText(group *ast.CommentGroup) string {
+	comments := make([]*ast.Comment, 0, len(group.List))
+	for _, comment := range group.List {
+		if strings.HasPrefix(comment.Text, "/*") {
+			comment = &ast.Comment{Slash: comment.Slash, Text: "/*" + undecorate(comment.Text[2:len(comment.Text)-2]) + "*/"}
+		}
+		comments = append(comments, comment)
+	}
+	return (&ast.CommentGroup{List: comments}).Text()
+}
+
+// undecorate removes the leading asterisks from the lines of the block comment, if all its lines but the first have one
+func undecorate(block string) string {
+	lines := strings.Split(block, "\n")
+	decorated := false
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "*") {
+			return block
+		}
+		decorated = true
+	}
+	if !decorated {
+		return block
+	}
+	for i := 1; i < len(lines); i++ {
+		trimmed := strings.TrimPrefix(strings.TrimLeft(lines[i], " \t"), "*")
+		lines[i] = strings.TrimPrefix(trimmed, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,261 @@
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestScanComments(t *testing.T) {
+	t.Parallel()
+
+	src := `// Package example is an example
+package example
+
+import "errors" // trailing import comment
+// not attached to the trailing comment
+
+/*
+	block comment
+*/
+// following the block comment
+
+// @fyi.error code example_code
+// @fyi.error title Example
+func example() error {
+	x := 1 /* inline */ + 2 // trailing
+	_ = x
+
+	// @fyi.error code other_code
+
+	// separate group
+	return errors.New("// not a comment")
+}
+`
+	texts := func(file *ast.File) []string {
+		var groups []string
+		for _, group := range file.Comments {
+			groups = append(groups, group.Text())
+		}
+		return groups
+	}
+
+	expected, err := goparser.ParseFile(token.NewFileSet(), "example.go", src, goparser.ParseComments)
+	require.NoError(t, err)
+	actual, err := scanComments(token.NewFileSet(), "example.go", []byte(src))
+	require.NoError(t, err)
+	assert.Equal(t, texts(expected), texts(actual))
+	assert.Equal(t, "example", actual.Name.Name)
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":    "package main\n\n// @fyi name app\n// @fyi version v1\nfunc main() {}\n",
+		"errors.go":  "package main\n\n// @fyi.error code not_found\n// @fyi.error title Not Found\nfunc find() {}\n",
+		"ignored.go": "//go:build ignore\n\npackage main\n\n// @fyi.error code ignored\nfunc ignored() {}\n",
+	})
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	p := NewParser(&Options{Logger: &logger})
+
+	t.Run("Successfully scan the comments of the files not defining errors", func(t *testing.T) {
+		t.Parallel()
+		loaded, err := p.loadFile(token.NewFileSet(), filepath.Join(root, "main.go"))
+		require.NoError(t, err)
+		assert.Empty(t, loaded.file.Decls)
+		require.Len(t, loaded.annotations, 1)
+		assert.Equal(t, "app", loaded.annotations[0].Name)
+	})
+	t.Run("Successfully parse the files defining errors", func(t *testing.T) {
+		t.Parallel()
+		loaded, err := p.loadFile(token.NewFileSet(), filepath.Join(root, "errors.go"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, loaded.file.Decls)
+		require.Len(t, loaded.annotations, 1)
+		definition := loaded.annotations[0].ErrorsDefinitions["not_found"]
+		require.NotNil(t, definition.Meta)
+		assert.Equal(t, "find", *definition.Meta.Function)
+	})
+	t.Run("Successfully skip the files excluded by their build constraints", func(t *testing.T) {
+		t.Parallel()
+		loaded, err := p.loadFile(token.NewFileSet(), filepath.Join(root, "ignored.go"))
+		require.NoError(t, err)
+		assert.Nil(t, loaded)
+	})
+	t.Run("Fail to load a missing file", func(t *testing.T) {
+		t.Parallel()
+		_, err := p.loadFile(token.NewFileSet(), filepath.Join(root, "missing.go"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestParserBlockComments(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"main.go": `package main
+
+/*
+ * @fyi name app
+ * @fyi base_url https://example.com
+ * @fyi version v0.1.0
+ */
+
+/*
+	@fyi.error code block_code
+	@fyi.error title Block Comment
+	@fyi.error short Annotations in a block comment.
+	@fyi.error long The first paragraph.
+
+	The second paragraph.
+*/
+func main() {}
+`,
+	})
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	specs, err := NewParser(&Options{
+		Logger:           &logger,
+		InputDirectories: []string{root},
+	}).Parse(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, specs, "app")
+	manifest := specs["app"].(*api.Manifest)
+	assert.Equal(t, "https://example.com", manifest.BaseUrl)
+	require.Contains(t, manifest.ErrorsDefinitions, "block_code")
+	definition := manifest.ErrorsDefinitions["block_code"]
+	assert.Equal(t, "Annotations in a block comment.", definition.Short)
+	require.NotNil(t, definition.Long)
+	assert.Equal(t, "The first paragraph.\n\nThe second paragraph.", *definition.Long)
+}
+
+// syntheticTree writes a tree of packages with annotated and plain go files, returning the number of error codes
+func syntheticTree(t testing.TB, root string, packages, filesPerPackage int) int {
+	t.Helper()
+	tree := map[string]string{
+		"go.mod":  "module example.com/synthetic\n",
+		"main.go": "package main\n\n// @fyi name synthetic\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\nfunc main() {}\n",
+	}
+	codes := 0
+	for i := 0; i < packages; i++ {
+		for j := 0; j < filesPerPackage; j++ {
+			name := fmt.Sprintf("pkg%03d/file%03d.go", i, j)
+			if j%4 == 0 {
+				tree[name] = errorAnnotation(fmt.Sprintf("pkg%03d", i), fmt.Sprintf("code_%03d_%03d", i, j)) +
+					"\n// Run does something\nfunc Run() error {\n\treturn nil\n}\n"
+				codes++
+				continue
+			}
+			tree[name] = fmt.Sprintf("package pkg%03d\n\n// Helper%d does something\nfunc Helper%d(a, b int) int {\n\treturn a + b\n}\n", i, j, j)
+		}
+	}
+	writeTree(t, root, tree)
+	return codes
+}
+
+func TestParserConcurrency(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	codes := syntheticTree(t, root, 20, 10)
+
+	parse := func(workers int) map[string]any {
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		specs, err := NewParser(&Options{
+			Logger:           &logger,
+			InputDirectories: []string{root},
+			Concurrency:      workers,
+		}).Parse(context.Background())
+		require.NoError(t, err)
+		return specs
+	}
+
+	sequential := parse(1)
+	require.Contains(t, sequential, "synthetic")
+	assert.Len(t, sequential["synthetic"].(*api.Manifest).ErrorsDefinitions, codes)
+	for _, workers := range []int{0, 4, 32} {
+		assert.Equal(t, sequential, parse(workers), "workers: %d", workers)
+	}
+}
+
+// BenchmarkParse parses a synthetic tree of 5000 go files, a quarter of them annotated
+func BenchmarkParse(b *testing.B) {
+	root := b.TempDir()
+	syntheticTree(b, root, 250, 20)
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	for _, bench := range []struct {
+		name    string
+		workers int
+		analyze bool
+	}{
+		{name: "sequential", workers: 1},
+		{name: "parallel", workers: 0},
+		{name: "sequential analyze", workers: 1, analyze: true},
+		{name: "parallel analyze", workers: 0, analyze: true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := NewParser(&Options{
+					Logger:           &logger,
+					InputDirectories: []string{filepath.Clean(root)},
+					Concurrency:      bench.workers,
+					Analyze:          bench.analyze,
+				}).Parse(context.Background())
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParserCache(t *testing.T) {
+	t.Parallel()
+
+	root, cacheDir := t.TempDir(), t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\n// @fyi name app\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\n",
+		"a/a.go":  errorAnnotation("a", "a_code"),
+	})
+
+	parse := func() *api.Manifest {
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		specs, err := NewParser(&Options{
+			Logger:           &logger,
+			InputDirectories: []string{root},
+			CacheDir:         cacheDir,
+		}).Parse(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, specs, "app")
+		return specs["app"].(*api.Manifest)
+	}
+
+	uncached := parse()
+	assert.Equal(t, uncached, parse(), "the cached annotations produce the same manifest")
+
+	writeTree(t, root, map[string]string{"a/a.go": errorAnnotation("a", "changed_code")})
+	changed := parse()
+	assert.Contains(t, changed.ErrorsDefinitions, "changed_code")
+	assert.NotContains(t, changed.ErrorsDefinitions, "a_code")
+}
@@ -0,0 +1,151 @@
+package golang
+
+import (
+	"bytes"
+	"go/build"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fileFilter selects the go files to parse, mirroring the files the go tool would build
+type fileFilter struct {
+	// exclude are the glob patterns of the files and directories to skip, see isExcluded
+	exclude []string
+	// includeTests includes the _test.go files
+	includeTests bool
+	// context evaluates the build constraints of the files, i.e: GOOS, GOARCH and build tags
+	context build.Context
+}
+
+// newFileFilter returns the filter for the given exclude patterns and build configuration.
+// Empty goos and goarch default to the GOOS and GOARCH environment variables, or the host platform.
+func newFileFilter(exclude []string, includeTests bool, goos, goarch string, tags []string) *fileFilter {
+	ctx := build.Default
+	if goos != "" {
+		ctx.GOOS = goos
+	}
+	if goarch != "" {
+		ctx.GOARCH = goarch
+	}
+	ctx.BuildTags = tags
+	return &fileFilter{
+		exclude:      exclude,
+		includeTests: includeTests,
+		context:      ctx,
+	}
+}
+
+// skipDir checks if the directory, under the included root directory, should be skipped.
+// Like the go tool, vendor, testdata, hidden and underscore prefixed directories are skipped by default.
+func (f *fileFilter) skipDir(root, dir string) bool {
+	if filepath.Clean(root) == filepath.Clean(dir) {
+		return false
+	}
+	name := filepath.Base(dir)
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	return isExcluded(root, dir, f.exclude)
+}
+
+// match checks if the go file, under the included root directory, should be parsed. The build constraints are evaluated
+// from the content of the file once it's read, see matchSource.
+func (f *fileFilter) match(root, filename string) bool {
+	if filepath.Ext(filename) != ".go" {
+		return false
+	}
+	if !f.includeTests && strings.HasSuffix(filename, "_test.go") {
+		return false
+	}
+	if isExcluded(root, filename, f.exclude) {
+		return false
+	}
+	// the parent directories are checked too, files might be matched without walking the tree, i.e: on update
+	rel, err := filepath.Rel(root, filepath.Dir(filename))
+	if err != nil {
+		return false
+	}
+	dir := root
+	for _, element := range strings.Split(rel, string(filepath.Separator)) {
+		if element == "." {
+			continue
+		}
+		dir = filepath.Join(dir, element)
+		if f.skipDir(root, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSource checks if the go file is built for the configured platform and build tags, evaluating the //go:build
+// constraints of its already read content and its _GOOS_GOARCH filename suffixes
+func (f *fileFilter) matchSource(filename string, src []byte) bool {
+	ctx := f.context
+	ctx.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(src)), nil
+	}
+	ok, err := ctx.MatchFile(filepath.Dir(filename), filepath.Base(filename))
+	return err == nil && ok
+}
+
+// isExcluded checks if the path matches one of the exclude glob patterns. The patterns are matched against the path
+// relative to the included directory, patterns without a slash are also matched against each path element,
+// i.e: "mocks" excludes any mocks directory. "**" matches any number of path elements, i.e: "internal/**/gen_*.go".
+func isExcluded(root, filename string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, filename)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	elements := strings.Split(rel, "/")
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+		if !strings.Contains(pattern, "/") {
+			for _, element := range elements {
+				if ok, _ := path.Match(pattern, element); ok {
+					return true
+				}
+			}
+			continue
+		}
+		// a matching parent directory excludes its content too
+		for i := 1; i <= len(elements); i++ {
+			if matchElements(strings.Split(pattern, "/"), elements[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchElements matches the path elements against the pattern elements, "**" matches zero or more elements
+func matchElements(pattern, elements []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elements); i++ {
+				if matchElements(pattern[1:], elements[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elements) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elements[0]); !ok {
+			return false
+		}
+		pattern, elements = pattern[1:], elements[1:]
+	}
+	return len(elements) == 0
+}
@@ -0,0 +1,94 @@
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExcluded(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/src/app")
+	tests := []struct {
+		path     string
+		patterns []string
+		excluded bool
+	}{
+		{"mocks", []string{"mocks"}, true},
+		{"internal/mocks/client.go", []string{"mocks"}, true},
+		{"internal/client.go", []string{"mocks"}, false},
+		{"internal/zz_generated.go", []string{"zz_*.go"}, true},
+		{"internal/api/gen/api.go", []string{"internal/**/gen"}, true},
+		{"internal/gen/api.go", []string{"internal/**/gen/*.go"}, true},
+		{"cmd/gen/api.go", []string{"internal/**/gen"}, false},
+		{"cmd/main.go", []string{"cmd/"}, true},
+		{"cmd/main.go", nil, false},
+		{".", []string{"*"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.excluded, isExcluded(root, filepath.Join(root, filepath.FromSlash(tt.path)), tt.patterns), "path %q, patterns %q", tt.path, tt.patterns)
+	}
+}
+
+func TestFileFilter(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":                "package main\n",
+		"main_test.go":           "package main\n",
+		"linux.go":               "//go:build linux\n\npackage main\n",
+		"windows.go":             "//go:build windows\n\npackage main\n",
+		"file_linux.go":          "package main\n",
+		"integration.go":         "//go:build integration\n\npackage main\n",
+		"vendor/dep/dep.go":      "package dep\n",
+		"testdata/data.go":       "package data\n",
+		".hidden/hidden.go":      "package hidden\n",
+		"_skip/skip.go":          "package skip\n",
+		"mocks/mock.go":          "package mocks\n",
+		"internal/internal.go":   "package internal\n",
+		"internal/README.md":     "readme\n",
+		"internal/gen/zz_gen.go": "package gen\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name     string
+		filter   *fileFilter
+		expected []string
+	}{
+		{
+			name:     "Successfully select the files built for the platform",
+			filter:   newFileFilter([]string{"mocks", "zz_*.go"}, false, "windows", "amd64", nil),
+			expected: []string{"internal/internal.go", "main.go", "windows.go"},
+		},
+		{
+			name:     "Successfully select the test files and the files satisfying the build tags",
+			filter:   newFileFilter(nil, true, "linux", "amd64", []string{"integration"}),
+			expected: []string{"file_linux.go", "integration.go", "internal/gen/zz_gen.go", "internal/internal.go", "linux.go", "main.go", "main_test.go", "mocks/mock.go"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var selected []string
+			for name := range files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if tt.filter.match(root, path) && tt.filter.matchSource(path, []byte(files[name])) {
+					selected = append(selected, name)
+				}
+			}
+			sort.Strings(selected)
+			assert.Equal(t, tt.expected, selected)
+		})
+	}
+}
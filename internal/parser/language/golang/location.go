@@ -0,0 +1,130 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// enclosingFunction returns the name of the function or method declaration containing, or documented by, the position.
+// Methods are named after their receiver type, i.e: (*Parser).Parse. An empty string is returned outside of functions.
+func enclosingFunction(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		if pos >= start && pos < fn.End() {
+			return funcName(fn)
+		}
+	}
+	return ""
+}
+
+// funcName returns the name of the function declaration, prefixed by the receiver type for methods
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	recv := fn.Recv.List[0].Type
+	pointer := false
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+	// drop the type parameters of generic receivers
+	switch expr := recv.(type) {
+	case *ast.IndexExpr:
+		recv = expr.X
+	case *ast.IndexListExpr:
+		recv = expr.X
+	}
+	name := ""
+	if ident, ok := recv.(*ast.Ident); ok {
+		name = ident.Name
+	}
+	if pointer {
+		return "(*" + name + ")." + fn.Name.Name
+	}
+	return name + "." + fn.Name.Name
+}
+
+// sourcePath returns the path of the file relative to the root of its module, using forward slashes.
+// The file is returned as it is if it isn't part of a go module.
+func (p *Parser) sourcePath(filename string) string {
+	if filename == "" || filename == "-" {
+		return filename
+	}
+	root, err := p.modules.Root(filepath.Dir(filename))
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// locate returns the position in the file of the line and column of the comment group text, the lines of the text are
+// aligned with the lines of the comments they were read from. The position of the comment group is returned if the text
+// can't be aligned, i.e: legacy annotations rewritten into the current grammar.
+func locate(fset *token.FileSet, group *ast.CommentGroup, text string, line, column int) token.Position {
+	position := fset.Position(group.Pos())
+	lines := strings.Split(text, "\n")
+	if line < 1 || line > len(lines) {
+		return position
+	}
+	if column < 1 {
+		column = 1
+	}
+
+	type commentLine struct {
+		text         string
+		line, column int
+	}
+	var comments []commentLine
+	for _, comment := range group.List {
+		start := fset.Position(comment.Slash)
+		for i, text := range strings.Split(comment.Text, "\n") {
+			c := commentLine{text: text, line: start.Line + i, column: 1}
+			if i == 0 {
+				c.column = start.Column
+			}
+			comments = append(comments, c)
+		}
+	}
+
+	next := 0
+	for i, text := range lines[:line] {
+		target := i == line-1
+		if text == "" && !target {
+			continue
+		}
+		for ; next < len(comments); next++ {
+			if index := strings.Index(comments[next].text, text); index >= 0 {
+				break
+			}
+		}
+		if next == len(comments) {
+			return position
+		}
+		if target {
+			c := comments[next]
+			position.Line, position.Column = c.line, c.column+strings.Index(c.text, text)+column-1
+			return position
+		}
+		next++
+	}
+	return position
+}
@@ -0,0 +1,164 @@
+package golang
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func TestParserLocations(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\n// @fyi name app\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\n",
+		"internal/store/store.go": `package store
+
+type Store[T any] struct{}
+
+// @fyi.error code package_code
+// @fyi.error title Package
+// @fyi.error short Package level annotation.
+
+// @fyi.error code doc_code
+// @fyi.error title Doc
+// @fyi.error short Function documentation annotation.
+func Get() error {
+	return nil
+}
+
+func (s *Store[T]) Put() error {
+	func() {
+		// @fyi.error code method_code
+		// @fyi.error title Method
+		// @fyi.error short Method annotation.
+	}()
+	return nil
+}
+`,
+	})
+
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	specs, err := NewParser(&Options{
+		Logger:           &logger,
+		InputDirectories: []string{root},
+	}).Parse(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, specs, "app")
+	definitions := specs["app"].(*api.Manifest).ErrorsDefinitions
+
+	for code, expected := range map[string]struct {
+		line, column int
+		function     string
+	}{
+		"package_code": {line: 5, column: 1},
+		"doc_code":     {line: 9, column: 1, function: "Get"},
+		"method_code":  {line: 18, column: 3, function: "(*Store).Put"},
+	} {
+		require.Contains(t, definitions, code)
+		meta := definitions[code].Meta
+		require.NotNil(t, meta)
+		require.NotNil(t, meta.Loc)
+		assert.Equal(t, "internal/store/store.go", meta.Loc.Path, code)
+		require.NotNil(t, meta.Loc.Line, code)
+		assert.Equal(t, expected.line, *meta.Loc.Line, code)
+		assert.Equal(t, expected.column, *meta.Loc.Column, code)
+		require.NotNil(t, meta.Package, code)
+		assert.Equal(t, "example.com/app/internal/store", *meta.Package, code)
+		if expected.function == "" {
+			assert.Nil(t, meta.Function, code)
+			continue
+		}
+		require.NotNil(t, meta.Function, code)
+		assert.Equal(t, expected.function, *meta.Function, code)
+	}
+}
+
+func TestParserDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\n\n// @fyi name app\n// @fyi colour blue\n",
+		"a/a.go": `package a
+
+func Get() {
+	/*
+	 * @fyi.error code a_code
+	 * @fyi.error title A
+	 * @fyi.error short A.
+	 *   @fyi.error titel Typo
+	 */
+}
+`,
+		"b/b.go": errorAnnotation("b", "b_code"),
+	}
+
+	for _, cached := range []bool{false, true} {
+		cached := cached
+		name := "Successfully locate the diagnostics in the source files"
+		if cached {
+			name += " read from the cache"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			writeTree(t, root, files)
+
+			logger := logging.NewStandardLogger()
+			logger = logger.SetLevel("none")
+			opts := &Options{Logger: &logger, InputDirectories: []string{root}}
+			if cached {
+				opts.CacheDir = t.TempDir()
+				_, err := NewParser(opts).Parse(context.Background())
+				require.NoError(t, err)
+			}
+			p := NewParser(opts)
+			_, err := p.Parse(context.Background())
+			require.NoError(t, err)
+
+			var found []string
+			for _, d := range p.Diagnostics() {
+				rel, err := filepath.Rel(root, d.Filename)
+				require.NoError(t, err)
+				found = append(found, fmt.Sprintf("%s:%d:%d: %s", rel, d.Line, d.Column, d.Message))
+			}
+			assert.Equal(t, []string{
+				`a/a.go:8:7: unknown key "titel" in @fyi.error, the statement is ignored`,
+				`main.go:4:4: unknown key "colour" in @fyi, the statement is ignored`,
+			}, found)
+		})
+	}
+
+	t.Run("Successfully keep the annotations of the files with syntax errors on update", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, files)
+
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+		_, err := p.Parse(context.Background())
+		require.NoError(t, err)
+
+		writeTree(t, root, map[string]string{"b/b.go": errorAnnotation("b", "b_code") + "func {\n"})
+		specs, err := p.Update(context.Background(), filepath.Join(root, "b", "b.go"))
+		require.NoError(t, err)
+		assert.Contains(t, specs["app"].(*api.Manifest).ErrorsDefinitions, "b_code")
+
+		diagnostics := p.Diagnostics()
+		require.Len(t, diagnostics, 3)
+		assert.Equal(t, diagnostic.Error, diagnostics[1].Severity)
+		assert.Equal(t, filepath.Join(root, "b", "b.go"), diagnostics[1].Filename)
+		assert.Contains(t, diagnostics[1].Message, "the file can't be parsed")
+	})
+}
@@ -0,0 +1,192 @@
+package golang
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+// goPackage is a package of the included directories, identified by its import path.
+// The directory is used as the path outside of go modules.
+type goPackage struct {
+	dir, path string
+	inModule  bool
+	// filenames are the loaded files of the package, main.go first
+	filenames []string
+}
+
+// owners decides the application owning each package, keyed by directory, so the errors are merged into the same
+// application regardless of the order the files are parsed in:
+//   - a package is owned by the first application named in it, by @fyi name;
+//   - otherwise by the application of its nearest parent package;
+//   - otherwise by the only application of the included directories, or the application of the packages sharing the
+//     longest directory prefix with it.
+func (p *Parser) owners(pkgs []goPackage) map[string]string {
+	owners := map[string]string{}
+	apps := map[string][]string{}
+	for _, pkg := range pkgs {
+		for _, filename := range pkg.filenames {
+			for _, partial := range p.files[filename].annotations {
+				if partial.Name == "" {
+					continue
+				}
+				owner, ok := owners[pkg.dir]
+				if !ok {
+					owners[pkg.dir] = partial.Name
+					apps[partial.Name] = append(apps[partial.Name], pkg.dir)
+					continue
+				}
+				if owner != partial.Name {
+					p.addDiagnostics(filename, diagnostic.List{{
+						Severity: diagnostic.Warning,
+						Message: fmt.Sprintf("the package is owned by the application %q, only the errors annotated "+
+							"alongside @fyi name %s belong to %q", owner, partial.Name, partial.Name),
+						Hint: "keep each application in its own packages",
+					}})
+				}
+			}
+		}
+	}
+
+	named := make(map[string]string, len(owners))
+	for dir, owner := range owners {
+		named[dir] = owner
+	}
+	for _, pkg := range pkgs {
+		if _, ok := named[pkg.dir]; ok {
+			continue
+		}
+		if owner, ok := parentOwner(named, pkg.dir); ok {
+			owners[pkg.dir] = owner
+			continue
+		}
+		owners[pkg.dir] = closestApplication(apps, pkg.dir)
+	}
+	return owners
+}
+
+// parentOwner returns the application of the nearest parent directory owned by one
+func parentOwner(named map[string]string, dir string) (string, bool) {
+	for parent := filepath.Dir(dir); parent != dir; dir, parent = parent, filepath.Dir(parent) {
+		if owner, ok := named[parent]; ok {
+			return owner, true
+		}
+	}
+	return "", false
+}
+
+// closestApplication returns the application whose packages share the longest directory prefix with the directory,
+// the ties are broken by name. It returns an empty string if there's no application.
+func closestApplication(apps map[string][]string, dir string) string {
+	names := make([]string, 0, len(apps))
+	for name := range apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	closest, longest := "", -1
+	for _, name := range names {
+		for _, appDir := range apps[name] {
+			if n := commonPrefix(appDir, dir); n > longest {
+				closest, longest = name, n
+			}
+		}
+	}
+	return closest
+}
+
+// commonPrefix returns the number of leading path elements shared by the directories
+func commonPrefix(a, b string) int {
+	aElems := strings.Split(filepath.ToSlash(filepath.Clean(a)), "/")
+	bElems := strings.Split(filepath.ToSlash(filepath.Clean(b)), "/")
+	n := 0
+	for n < len(aElems) && n < len(bElems) && aElems[n] == bElems[n] {
+		n++
+	}
+	return n
+}
+
+// resolveReferences replaces the placeholder codes of the errors documenting a call whose code is a constant declared
+// in another file of the package, siblings are the files of the package. The errors of unresolved constants are reported
+// and skipped. The partial specifications are copied rather than modified, they are shared with the cache.
+func (p *Parser) resolveReferences(filename string, siblings []string, annotations []*api.Manifest) []*api.Manifest {
+	resolved := annotations
+	// the slice of annotations is copied before the first resolved specification replaces its partial one
+	copied := false
+	for i, partial := range annotations {
+		var definitions api.ErrorDefinitions
+		for key, definition := range partial.ErrorsDefinitions {
+			name, ok := isReference(key)
+			if !ok {
+				continue
+			}
+			if definitions == nil {
+				definitions = make(api.ErrorDefinitions, len(partial.ErrorsDefinitions))
+				for k, v := range partial.ErrorsDefinitions {
+					definitions[k] = v
+				}
+			}
+			delete(definitions, key)
+
+			code, ok := p.packageConstant(siblings, name)
+			if !ok {
+				d := diagnostic.Diagnostic{
+					Severity: diagnostic.Error,
+					Message:  fmt.Sprintf("the code of the documented call is %s, which isn't a string constant of the package, the error is skipped", name),
+					Hint:     "add a @fyi.error code statement to the annotations",
+				}
+				if definition.Meta != nil && definition.Meta.Loc != nil && definition.Meta.Loc.Line != nil {
+					d.Line, d.Column = *definition.Meta.Loc.Line, *definition.Meta.Loc.Column
+				}
+				p.addDiagnostics(filename, diagnostic.List{d})
+				continue
+			}
+			definition.Code = code
+			definitions[code] = definition
+		}
+		if definitions == nil {
+			continue
+		}
+
+		if !copied {
+			resolved = append([]*api.Manifest(nil), annotations...)
+			copied = true
+		}
+		manifest := *partial
+		manifest.ErrorsDefinitions = definitions
+		resolved[i] = &manifest
+	}
+	return resolved
+}
+
+// packageConstant looks up the string constant in the files of the package, their constants are loaded on first use
+func (p *Parser) packageConstant(filenames []string, name string) (string, bool) {
+	for _, filename := range filenames {
+		file, ok := p.files[filename]
+		constants := map[string]string(nil)
+		if ok {
+			constants = file.constants
+		}
+		if constants == nil {
+			loaded, err := loadConstants(filename)
+			if err != nil {
+				p.logger.Debug("Skipping the constants of the file", "file", filename, "error", err)
+			}
+			constants = loaded
+			if ok {
+				file.constants = loaded
+				if file.constants == nil {
+					file.constants = map[string]string{}
+				}
+			}
+		}
+		if code, ok := constants[name]; ok {
+			return code, true
+		}
+	}
+	return "", false
+}
@@ -2,7 +2,7 @@ package golang
 
 import (
 	"context"
-	"github.com/microcosm-cc/bluemonday"
+	"fmt"
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
@@ -10,11 +10,15 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/cache"
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/fragment"
 	"github.com/tfadeyi/errors/internal/logging"
-	"github.com/tfadeyi/errors/internal/parser/grammar"
+	"github.com/tfadeyi/errors/internal/module"
 	"github.com/tfadeyi/errors/pkg/api"
 )
 
@@ -28,7 +32,35 @@ type Parser struct {
 	// sourceContent is the reader to the content to be parsed
 	sourceContent io.ReadCloser
 	includedDirs  []string
-	logger        *logging.Logger
+	// filter selects the go files parsed in the included directories
+	filter *fileFilter
+	// modules resolves the import paths of the parsed packages
+	modules *module.Resolver
+	// workspaceModules are the root directories of the modules of the go.work workspace, if any, containing the
+	// included directories. Nested modules outside the workspace are skipped.
+	workspaceModules map[string]bool
+	logger           *logging.Logger
+	// annotationPrefixes are the legacy annotation prefixes accepted alongside @fyi
+	annotationPrefixes []string
+	// packages contains the annotation coverage statistics of the parsed packages, keyed by directory
+	packages map[string]*packageStats
+	// fset is the file set of the parsed files
+	fset *token.FileSet
+	// files contains the loaded go files of the included directories, keyed by filename.
+	// They are kept so Update only re-parses the files that changed.
+	files map[string]*sourceFile
+	// concurrency is the maximum number of files loaded concurrently
+	concurrency int
+	// analyze configures the parser to parse the whole files, not only their comments, to collect the statistics
+	analyze bool
+	// cacheDir is the directory of the parse cache, the cache is disabled if empty
+	cacheDir string
+	cache    *cache.Cache
+	// diagnostics are the problems found in the annotations by the last parsing, sorted by location
+	diagnostics diagnostic.List
+	// fragments are the glob patterns, relative to the included directories, of the fragment files merged into
+	// the errors of the annotations
+	fragments []string
 }
 
 // Options contains the configuration options available to the Parser
@@ -39,6 +71,27 @@ type Options struct {
 	// SourceContent is the reader to the content to be parsed
 	SourceContent    io.ReadCloser
 	InputDirectories []string
+	// ExcludePatterns are the glob patterns, relative to the input directories, of the files and directories to skip
+	ExcludePatterns []string
+	// IncludeTests includes the _test.go files of the input directories
+	IncludeTests bool
+	// GOOS and GOARCH are the target platform the build constraints are evaluated for, they default to the host platform
+	GOOS, GOARCH string
+	// BuildTags are the build tags satisfied while evaluating the build constraints of the files
+	BuildTags []string
+	// AnnotationPrefixes are the legacy annotation prefixes, i.e: @aloe, accepted alongside @fyi
+	AnnotationPrefixes []string
+	// Concurrency is the maximum number of files parsed concurrently, it defaults to GOMAXPROCS
+	Concurrency int
+	// Analyze parses the whole files of the input directories, rather than only their comments,
+	// so the annotation coverage statistics can be collected
+	Analyze bool
+	// CacheDir is the directory the annotations of the parsed files are cached in, so the unchanged files are skipped
+	// by the following parsing. The cache is disabled if empty.
+	CacheDir string
+	// Fragments are the glob patterns, relative to the input directories, of the YAML or JSON fragment files completing
+	// the errors of the annotations. They default to fragment.DefaultPatterns if nil, no fragments are read if empty.
+	Fragments []string
 }
 
 // NewParser client Parser performs all checks at initialization time
@@ -52,6 +105,10 @@ func NewParser(opts *Options) *Parser {
 	dirs := opts.InputDirectories
 	sourceFile := opts.SourceFile
 	sourceContent := opts.SourceContent
+	fragments := opts.Fragments
+	if fragments == nil {
+		fragments = fragment.DefaultPatterns
+	}
 
 	return &Parser{
 		specs:         map[string]any{},
@@ -60,47 +117,94 @@ func NewParser(opts *Options) *Parser {
 		sourceContent: sourceContent,
 		includedDirs:  dirs,
 		logger:        logger,
-	}
-}
 
-// getAllGoPackages fetches all the available golang packages in the target directory and subdirectories
-func getAllGoPackages(dir string) (map[string]*ast.Package, error) {
-	fset := token.NewFileSet()
-	pkgs, err := goparser.ParseDir(fset, dir, nil, goparser.ParseComments)
-	if err != nil {
-		return map[string]*ast.Package{}, err
+		modules:            module.NewResolver(),
+		filter:             newFileFilter(opts.ExcludePatterns, opts.IncludeTests, opts.GOOS, opts.GOARCH, opts.BuildTags),
+		annotationPrefixes: opts.AnnotationPrefixes,
+		concurrency:        opts.Concurrency,
+		analyze:            opts.Analyze,
+		cacheDir:           opts.CacheDir,
+		fragments:          fragments,
 	}
+}
 
-	// walk through the directories and parse the not already found go packages
-	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+// getAllGoFiles returns the go files, selected by the filter, in the target directory and subdirectories
+func (p *Parser) getAllGoFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 		if d.IsDir() {
-			foundPkgs, err := goparser.ParseDir(fset, path, nil, goparser.ParseComments)
-			if err != nil {
-				return err
-			}
-			for pkgName, pkg := range foundPkgs {
-				if _, ok := pkgs[pkgName]; !ok {
-					pkgs[pkgName] = pkg
-				}
+			if p.filter.skipDir(dir, path) || p.isNestedModule(dir, path) {
+				return filepath.SkipDir
 			}
+			return nil
 		}
-		return err
+		if !p.filter.match(dir, path) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(pkgs) == 0 {
-		return nil, errors.Errorf("no go packages were found in the target directory and subdirectories: %s", dir)
+	if len(files) == 0 {
+		return nil, errors.Errorf("no go files were found in the target directory and subdirectories: %s", dir)
 	}
 
-	return pkgs, nil
+	return files, nil
+}
+
+// isNestedModule checks if the directory, under the included root directory, is the root of another go module outside
+// the workspace. Like the go tool, the packages of nested modules don't belong to the parent module.
+func (p *Parser) isNestedModule(root, dir string) bool {
+	if filepath.Clean(root) == filepath.Clean(dir) {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(dir, module.GoModFilename)); err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return true
+	}
+	return !p.workspaceModules[abs]
+}
+
+// loadWorkspace finds the go.work workspace of the included directories, if any
+func (p *Parser) loadWorkspace() {
+	p.workspaceModules = map[string]bool{}
+	for _, dir := range p.includedDirs {
+		workFile, err := module.FindWorkspace(dir)
+		if err != nil {
+			continue
+		}
+		roots, err := module.WorkspaceModules(workFile)
+		if err != nil {
+			p.warn(err)
+			continue
+		}
+		for _, root := range roots {
+			p.workspaceModules[root] = true
+		}
+	}
+}
+
+// packagePath returns the import path of the package in the directory, or the directory if it isn't part of a go module
+func (p *Parser) packagePath(dir string) (string, bool) {
+	importPath, err := p.modules.ImportPath(dir)
+	if err != nil {
+		return dir, false
+	}
+	return importPath, true
 }
 
 // getFile returns the ast go file struct given filename or an io.Reader. If an io.Reader is passed it will take precedence
 // over the filename
-func getFile(name string, file io.ReadCloser) (*ast.File, error) {
-	fset := token.NewFileSet()
+func getFile(fset *token.FileSet, name string, file io.ReadCloser) (*ast.File, error) {
 	if file != nil {
 		defer func(file io.ReadCloser) {
 			err := file.Close()
@@ -112,79 +216,97 @@ func getFile(name string, file io.ReadCloser) (*ast.File, error) {
 	return goparser.ParseFile(fset, name, file, goparser.ParseComments)
 }
 
-func (p *Parser) parseErrorAnnotations(filename string, comments ...*ast.CommentGroup) error {
-	if p.current == nil {
-		p.current = &api.Manifest{
-			BaseUrl:           "",
-			Description:       nil,
-			ErrorsDefinitions: api.ErrorDefinitions{},
-			Name:              "",
-			Title:             nil,
-			Version:           "",
-		}
-	}
+// parseErrorAnnotations merges the partial specifications of the file's annotations into the parsed specifications,
+// pkg is the import path of the file's package and owner the application owning it. The annotations naming an application
+// are merged into it, the others into the owner. The partial specifications are never modified, so they can be reused
+// by the following parsing.
+func (p *Parser) parseErrorAnnotations(filename, pkg, owner string, annotations ...*api.Manifest) error {
+	p.current = p.application(owner)
 
-	p.logger.Debug("Current application being parsed", "application", p.current.(*api.Manifest).Name)
-	for _, comment := range comments {
-		if !strings.HasPrefix(strings.TrimSpace(comment.Text()), "@fyi") {
-			continue
-		}
-
-		commentString := bluemonday.UGCPolicy().Sanitize(strings.TrimSpace(comment.Text()))
-
-		p.logger.Debug("Parsing", "comment", commentString)
-		// partialServiceSpec contains the partially parsed sloth Specification for a given comment group
-		// this means the parsed spec will only contain data for the fields that are present in the comments, making the spec only partially accurate
-		partialServiceSpec, err := grammar.Eval(commentString)
-		if err != nil {
-			p.warn(err)
-			continue
-		}
-
-		// if the comment group contains a reference to the service name
-		// check if service was parsed before else add it the collection of specs.
-		// Set the found service spec as the current service spec.
+	p.logger.Debug("Current application being parsed", "application", owner)
+	for _, partialServiceSpec := range annotations {
+		spec := p.current.(*api.Manifest)
 		if partialServiceSpec.Name != "" {
-			if p.current != nil && (p.current.(*api.Manifest).Name == partialServiceSpec.Name ||
-				p.current.(*api.Manifest).Name == "") {
-				p.specs[partialServiceSpec.Name] = p.current
-			}
-
-			spec, ok := p.specs[partialServiceSpec.Name]
-			if !ok {
-				p.specs[partialServiceSpec.Name] = partialServiceSpec
-				p.current = partialServiceSpec
-			} else {
-				p.current = spec.(*api.Manifest)
-			}
+			spec = p.application(partialServiceSpec.Name)
 		}
 
-		if p.current.(*api.Manifest).Name == "" {
-			p.current.(*api.Manifest).Name = partialServiceSpec.Name
+		if spec.Version == "" {
+			spec.Version = partialServiceSpec.Version
 		}
-		if p.current.(*api.Manifest).Version == "" {
-			p.current.(*api.Manifest).Version = partialServiceSpec.Version
+		if spec.BaseUrl == "" {
+			spec.BaseUrl = partialServiceSpec.BaseUrl
 		}
-		if p.current.(*api.Manifest).BaseUrl == "" {
-			p.current.(*api.Manifest).BaseUrl = partialServiceSpec.BaseUrl
+		if spec.Description == nil {
+			spec.Description = partialServiceSpec.Description
 		}
-		if p.current.(*api.Manifest).Description == nil {
-			p.current.(*api.Manifest).Description = partialServiceSpec.Description
+		if spec.Title == nil {
+			spec.Title = partialServiceSpec.Title
 		}
-		if p.current.(*api.Manifest).Title == nil {
-			p.current.(*api.Manifest).Title = partialServiceSpec.Title
+
+		if spec.Name == "" {
+			for _, definition := range partialServiceSpec.ErrorsDefinitions {
+				d := diagnostic.Diagnostic{
+					Severity: diagnostic.Warning,
+					Message:  fmt.Sprintf("no application owns the error %q, the error is skipped", definition.Code),
+					Hint:     "name the application of the package, or of one of its parent packages, with @fyi name",
+				}
+				if definition.Meta != nil && definition.Meta.Loc != nil && definition.Meta.Loc.Line != nil {
+					d.Line, d.Column = *definition.Meta.Loc.Line, *definition.Meta.Loc.Column
+				}
+				p.addDiagnostics(filename, diagnostic.List{d})
+			}
+			continue
 		}
 
+		statsKey := pkg
+		if statsKey == "" {
+			statsKey = filepath.Dir(filename)
+		}
+		defined := p.packageStats(statsKey).defined
 		for key, definition := range partialServiceSpec.ErrorsDefinitions {
-			definition.Meta = &api.ErrorMeta{Loc: &api.ErrorMetaLoc{
-				Path: filepath.Base(filename),
-			}}
-			p.current.(*api.Manifest).ErrorsDefinitions[key] = definition
+			defined[key] = struct{}{}
+			// the metadata is copied, the partial specifications are shared with the cache
+			meta := api.ErrorMeta{}
+			if definition.Meta != nil {
+				meta = *definition.Meta
+			}
+			loc := api.ErrorMetaLoc{}
+			if meta.Loc != nil {
+				loc = *meta.Loc
+			}
+			loc.Path = p.sourcePath(filename)
+			meta.Loc = &loc
+			if pkg != "" {
+				importPath := pkg
+				meta.Package = &importPath
+			}
+			definition.Meta = &meta
+			spec.ErrorsDefinitions[key] = definition
 		}
 	}
 	return nil
 }
 
+// application returns the specification of the named application, it's created on first use.
+// The specification of the errors without an application isn't collected.
+func (p *Parser) application(name string) *api.Manifest {
+	if spec, ok := p.specs[name]; ok {
+		return spec.(*api.Manifest)
+	}
+	spec := &api.Manifest{
+		BaseUrl:           "",
+		Description:       nil,
+		ErrorsDefinitions: api.ErrorDefinitions{},
+		Name:              name,
+		Title:             nil,
+		Version:           "",
+	}
+	if name != "" {
+		p.specs[name] = spec
+	}
+	return spec
+}
+
 func (p *Parser) warn(err error, keyValues ...interface{}) {
 	if p.logger != nil {
 		p.logger.Warn(err, keyValues...)
@@ -195,23 +317,56 @@ func (p *Parser) warn(err error, keyValues ...interface{}) {
 // In case of error during parsing, Parse returns an empty sloth.Spec
 func (p *Parser) Parse(ctx context.Context) (map[string]any, error) {
 	// collect all sloth annotations from the file and add them to the spec struct
+	p.reset()
+	fset := token.NewFileSet()
 	if p.sourceFile != "" || p.sourceContent != nil {
-		file, err := getFile(p.sourceFile, p.sourceContent)
+		file, err := getFile(fset, p.sourceFile, p.sourceContent)
 		if err != nil {
 			// error hard as we can't extract more data for the spec
 			return nil, err
 		}
 
 		p.logger.Debug("Parsing source code", "file", file.Name)
-		if err := p.parseErrorAnnotations("", file.Comments...); err != nil {
+		pkg := ""
+		if p.sourceFile != "" {
+			if importPath, ok := p.packagePath(filepath.Dir(p.sourceFile)); ok {
+				pkg = importPath
+			}
+		}
+		annotations, diagnostics := p.evalAnnotations(fset, p.sourceFile, file)
+		filename := p.sourceFile
+		if filename == "" {
+			filename = "<stdin>"
+		}
+		p.addDiagnostics(filename, diagnostics)
+		var siblings []string
+		if p.sourceFile != "" {
+			siblings, _ = filepath.Glob(filepath.Join(filepath.Dir(p.sourceFile), "*.go"))
+		}
+		annotations = p.resolveReferences(filename, siblings, annotations)
+		// the file is owned by the first application it names
+		owner := ""
+		for _, partial := range annotations {
+			if partial.Name != "" {
+				owner = partial.Name
+				break
+			}
+		}
+		if err := p.parseErrorAnnotations(p.sourceFile, pkg, owner, annotations...); err != nil {
 			return nil, err
 		}
+		if pkg == "" {
+			pkg = filepath.Dir(p.sourceFile)
+		}
+		p.analyzeFile(pkg, fset, file)
 
 		p.logger.Debug("Parsed source code", "file", file.Name)
 		return p.specs, nil
 	}
 
-	applicationPackages := map[string]*ast.Package{}
+	p.fset = fset
+	p.loadWorkspace()
+	var filenames []string
 	for _, dir := range p.includedDirs {
 		// handle signals with context
 		select {
@@ -225,42 +380,142 @@ func (p *Parser) Parse(ctx context.Context) (map[string]any, error) {
 			p.warn(err)
 			continue
 		}
-		foundPkgs, err := getAllGoPackages(dir)
+		files, err := p.getAllGoFiles(dir)
 		if err != nil {
 			p.warn(err)
 			continue
 		}
+		filenames = append(filenames, files...)
+	}
 
-		for pkgName, pkg := range foundPkgs {
-			if _, ok := applicationPackages[pkgName]; !ok {
-				applicationPackages[pkgName] = pkg
-			}
+	p.openCache()
+	files, err := p.loadFiles(ctx, fset, filenames)
+	if err != nil {
+		return nil, err
+	}
+	p.files = files
+	p.saveCache()
+
+	return p.parseFiles(ctx)
+}
+
+// Update re-parses the given changed files, reusing the previously parsed files for the rest of the included directories,
+// and returns the updated specifications. Deleted files, and the files under deleted directories, are dropped, files
+// that can't be parsed keep their previous content.
+// Parse is used if the included directories were never parsed.
+func (p *Parser) Update(ctx context.Context, changed ...string) (map[string]any, error) {
+	if p.files == nil || p.sourceFile != "" || p.sourceContent != nil {
+		return p.Parse(ctx)
+	}
+
+	var filenames []string
+	for _, filename := range changed {
+		if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+			p.removeFiles(filename)
+			continue
 		}
+		if !p.isIncluded(filename) {
+			continue
+		}
+
+		p.logger.Debug("Re-parsing source code", "file", filename)
+		filenames = append(filenames, filename)
 	}
 
-	// collect all sloth annotations from packages and add them to the spec struct
-	for _, pkg := range applicationPackages {
-		// Prioritise parsing the main.go if present in the package
-		for filename, file := range pkg.Files {
-			if strings.Contains(filename, "main.go") {
-				p.logger.Debug("Parsing source code", "package", pkg.Name, "file", filename)
+	files, err := p.loadFiles(ctx, p.fset, filenames)
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range filenames {
+		if _, ok := files[filename]; !ok {
+			// the build constraints of the file exclude it now, or it can't be read anymore
+			p.removeFiles(filename)
+		}
+	}
+	for filename, file := range files {
+		if previous, ok := p.files[filename]; ok && file.failed {
+			// keep the previous content of the file until its syntax errors are fixed
+			file.file, file.annotations = previous.file, previous.annotations
+		}
+		p.files[filename] = file
+	}
+	p.saveCache()
 
-				if err := p.parseErrorAnnotations(filename, file.Comments...); err != nil {
-					p.warn(err)
-					break
-				}
+	return p.parseFiles(ctx)
+}
 
-				p.logger.Debug("Parsed source code", "package", pkg.Name, "file", filename)
-				break
+// removeFiles drops the deleted file, or the files under the deleted directory, from the loaded files
+func (p *Parser) removeFiles(path string) {
+	for filename := range p.files {
+		if filename != path && !strings.HasPrefix(filename, path+string(filepath.Separator)) {
+			continue
+		}
+		p.logger.Debug("Removing deleted source code", "file", filename)
+		delete(p.files, filename)
+		if p.cache != nil {
+			p.cache.Remove(cacheKey(filename))
+		}
+	}
+}
+
+// isIncluded checks if the file is under one of the included directories and selected by the filter
+func (p *Parser) isIncluded(filename string) bool {
+	for _, dir := range p.includedDirs {
+		rel, err := filepath.Rel(dir, filename)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		// files of nested modules outside the workspace are skipped, like when walking the included directories
+		for parent := filepath.Dir(filename); parent != filepath.Clean(dir) && parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+			if p.isNestedModule(dir, parent) {
+				return false
 			}
 		}
+		return p.filter.match(dir, filename)
+	}
+	return false
+}
 
-		// parse the rest of the files, skipping main.go
-		for filename, file := range pkg.Files {
-			if strings.Contains(filename, "main.go") {
-				continue
+// parseFiles merges the annotations of the loaded files into fresh specifications.
+// The files are visited by package, prioritising the main.go of each package, so the output is deterministic regardless
+// of the order the files were loaded in.
+func (p *Parser) parseFiles(ctx context.Context) (map[string]any, error) {
+	p.reset()
+
+	dirs := map[string][]string{}
+	for filename := range p.files {
+		dir := filepath.Dir(filename)
+		dirs[dir] = append(dirs[dir], filename)
+	}
+
+	pkgs := make([]goPackage, 0, len(dirs))
+	for dir, filenames := range dirs {
+		importPath, inModule := p.packagePath(dir)
+		sort.Slice(filenames, func(i, j int) bool {
+			// Prioritise parsing the main.go if present in the package
+			iMain, jMain := filepath.Base(filenames[i]) == "main.go", filepath.Base(filenames[j]) == "main.go"
+			if iMain != jMain {
+				return iMain
 			}
-			p.logger.Debug("Parsing source code", "package", pkg.Name, "file", filename)
+			return filenames[i] < filenames[j]
+		})
+		pkgs = append(pkgs, goPackage{dir: dir, path: importPath, inModule: inModule, filenames: filenames})
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].path != pkgs[j].path {
+			return pkgs[i].path < pkgs[j].path
+		}
+		return pkgs[i].dir < pkgs[j].dir
+	})
+	owners := p.owners(pkgs)
+
+	// collect all annotations from packages and add them to the spec struct
+	for _, pkg := range pkgs {
+		metaPackage := ""
+		if pkg.inModule {
+			metaPackage = pkg.path
+		}
+		for _, filename := range pkg.filenames {
 			// handle signals with context
 			select {
 			case <-ctx.Done():
@@ -268,21 +523,72 @@ func (p *Parser) Parse(ctx context.Context) (map[string]any, error) {
 			default:
 			}
 
-			if err := p.parseErrorAnnotations(filename, file.Comments...); err != nil {
+			p.logger.Debug("Parsing source code", "package", pkg.path, "file", filename)
+			file := p.files[filename]
+			p.addDiagnostics(filename, file.diagnostics)
+			annotations := p.resolveReferences(filename, pkg.filenames, file.annotations)
+			if err := p.parseErrorAnnotations(filename, metaPackage, owners[pkg.dir], annotations...); err != nil {
 				p.warn(err)
 				continue
 			}
+			if p.analyze {
+				p.analyzeFile(pkg.path, p.fset, file.file)
+			}
 
-			p.logger.Debug("Parsed source code", "package", pkg.Name, "file", filename)
+			p.logger.Debug("Parsed source code", "package", pkg.path, "file", filename)
 		}
 	}
 
+	p.mergeFragments()
+
 	// print statistics
 	p.stats()
+	p.diagnostics.Sort()
 
 	return p.specs, nil
 }
 
+// mergeFragments merges the fragment files of the included directories into the errors of the parsed specifications
+func (p *Parser) mergeFragments() {
+	filenames, err := fragment.Find(p.includedDirs, p.fragments)
+	if err != nil {
+		p.warn(errors.Annotate(err, "failed to find the fragment files"))
+		return
+	}
+	if len(filenames) == 0 {
+		return
+	}
+
+	p.logger.Debug("Merging the fragment files", "files", filenames)
+	fragments, diagnostics := fragment.Load(filenames...)
+	diagnostics = append(diagnostics, fragment.Merge(p.specs, fragments, p.sourcePath)...)
+	for _, d := range diagnostics {
+		p.addDiagnostics(d.Filename, diagnostic.List{d})
+	}
+}
+
+// reset discards the specifications and statistics collected by the previous parsing
+func (p *Parser) reset() {
+	p.specs = map[string]any{}
+	p.current = nil
+	p.packages = nil
+	p.diagnostics = nil
+}
+
+// addDiagnostics locates the diagnostics of the file in it and adds them to the diagnostics of the parsing
+func (p *Parser) addDiagnostics(filename string, diagnostics diagnostic.List) {
+	for _, d := range diagnostics {
+		d.Filename = filename
+		p.logger.Debug("Found a problem in the annotations", "diagnostic", d.String())
+		p.diagnostics = append(p.diagnostics, d)
+	}
+}
+
+// Diagnostics returns the problems found in the annotations by the last Parse or Update call
+func (p *Parser) Diagnostics() diagnostic.List {
+	return p.diagnostics
+}
+
 func (p *Parser) stats() {
 	for _, spec := range p.specs {
 		s := spec.(*api.Manifest)
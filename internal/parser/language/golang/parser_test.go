@@ -1 +1,184 @@
 package golang
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+func writeTree(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func errorAnnotation(pkg, code string) string {
+	return "package " + pkg + "\n\n// @fyi.error code " + code + "\n// @fyi.error title Title\n// @fyi.error short Short.\n"
+}
+
+func TestParserModules(t *testing.T) {
+	t.Parallel()
+
+	tree := map[string]string{
+		"go.mod":                   "module example.com/app\n",
+		"main.go":                  "package main\n\n// @fyi name app\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\n",
+		"a/options/options.go":     errorAnnotation("options", "a_code"),
+		"b/options/options.go":     errorAnnotation("options", "b_code"),
+		"nested/go.mod":            "module example.com/nested\n",
+		"nested/options/nested.go": errorAnnotation("options", "nested_code"),
+	}
+
+	parse := func(t *testing.T, root string) *api.Manifest {
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		specs, err := NewParser(&Options{
+			Logger:           &logger,
+			InputDirectories: []string{root},
+		}).Parse(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, specs, "app")
+		return specs["app"].(*api.Manifest)
+	}
+	packages := func(manifest *api.Manifest) map[string]string {
+		result := map[string]string{}
+		for code, definition := range manifest.ErrorsDefinitions {
+			require.NotNil(t, definition.Meta)
+			require.NotNil(t, definition.Meta.Package)
+			result[code] = *definition.Meta.Package
+		}
+		return result
+	}
+
+	t.Run("Successfully parse the packages sharing a name, skipping the nested modules", func(t *testing.T) {
+		root := t.TempDir()
+		writeTree(t, root, tree)
+
+		assert.Equal(t, map[string]string{
+			"a_code": "example.com/app/a/options",
+			"b_code": "example.com/app/b/options",
+		}, packages(parse(t, root)))
+	})
+	t.Run("Successfully parse the nested modules of the workspace", func(t *testing.T) {
+		root := t.TempDir()
+		writeTree(t, root, tree)
+		writeTree(t, root, map[string]string{"go.work": "go 1.20\n\nuse (\n\t.\n\t./nested\n)\n"})
+
+		found := packages(parse(t, root))
+		codes := make([]string, 0, len(found))
+		for code := range found {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		assert.Equal(t, []string{"a_code", "b_code", "nested_code"}, codes)
+		assert.Equal(t, "example.com/nested/options", found["nested_code"])
+	})
+}
+
+func TestParserFragments(t *testing.T) {
+	t.Parallel()
+
+	tree := map[string]string{
+		"go.mod":            "module example.com/app\n",
+		"main.go":           "package main\n\n// @fyi name app\n",
+		"store/store.go":    errorAnnotation("store", "not_found"),
+		"errors.d/a.yaml":   "errors_definitions:\n  not_found:\n    long: |\n      The resource wasn't found,\n      check its name.\n",
+		"errors.d/b.json":   `{"errors_definitions": {"unknown": {"title": "Unknown"}}}`,
+		"errors.d/notes.md": "not a fragment",
+	}
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+
+	t.Run("Successfully merge the fragment files into the errors of the annotations", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, tree)
+
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+		specs, err := p.Parse(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, specs, "app")
+		definition := specs["app"].(*api.Manifest).ErrorsDefinitions["not_found"]
+		require.NotNil(t, definition.Long)
+		assert.Equal(t, "The resource wasn't found,\ncheck its name.", *definition.Long)
+		assert.Equal(t, "Title", definition.Title)
+		require.NotNil(t, definition.Meta)
+		assert.Equal(t, "store/store.go", definition.Meta.Loc.Path)
+		assert.Equal(t, "errors.d/a.yaml", definition.Meta.Sources["long"].Path)
+
+		require.Len(t, p.Diagnostics(), 1)
+		assert.Equal(t, filepath.Join(root, "errors.d", "b.json"), p.Diagnostics()[0].Filename)
+	})
+	t.Run("Successfully skip the fragment files", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, tree)
+
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}, Fragments: []string{}})
+		specs, err := p.Parse(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, specs["app"].(*api.Manifest).ErrorsDefinitions["not_found"].Long)
+		assert.Empty(t, p.Diagnostics())
+	})
+}
+
+func TestParserUpdate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully drop the files of a deleted directory", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{
+			"go.mod":          "module example.com/app\n",
+			"main.go":         "package main\n\n// @fyi name app\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\n",
+			"kept/kept.go":    errorAnnotation("kept", "kept_code"),
+			"store/store.go":  errorAnnotation("store", "store_code"),
+			"store/sql/db.go": errorAnnotation("sql", "sql_code"),
+		})
+
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+		specs, err := p.Parse(context.Background())
+		require.NoError(t, err)
+		require.Len(t, specs["app"].(*api.Manifest).ErrorsDefinitions, 3)
+
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "store")))
+		specs, err = p.Update(context.Background(), filepath.Join(root, "store"))
+		require.NoError(t, err)
+		definitions := specs["app"].(*api.Manifest).ErrorsDefinitions
+		assert.Len(t, definitions, 1)
+		assert.Contains(t, definitions, "kept_code")
+	})
+	t.Run("Successfully drop the files excluded by their updated build constraints", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{
+			"go.mod":         "module example.com/app\n",
+			"main.go":        "package main\n\n// @fyi name app\n// @fyi base_url https://example.com\n// @fyi version v0.1.0\n",
+			"store/store.go": errorAnnotation("store", "store_code"),
+		})
+
+		logger := logging.NewStandardLogger()
+		logger = logger.SetLevel("none")
+		p := NewParser(&Options{Logger: &logger, InputDirectories: []string{root}})
+		specs, err := p.Parse(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, specs["app"].(*api.Manifest).ErrorsDefinitions, "store_code")
+
+		filename := filepath.Join(root, "store", "store.go")
+		require.NoError(t, os.WriteFile(filename, []byte("//go:build ignore\n\n"+errorAnnotation("store", "store_code")), 0644))
+		specs, err = p.Update(context.Background(), filename)
+		require.NoError(t, err)
+		assert.NotContains(t, specs["app"].(*api.Manifest).ErrorsDefinitions, "store_code")
+	})
+}
@@ -0,0 +1,248 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+
+	"github.com/tfadeyi/errors/internal/stats"
+	"github.com/tfadeyi/errors/pkg/api"
+)
+
+const (
+	// libraryImportPath is the import path of the error.fyi library wrapping errors with error codes
+	libraryImportPath = "github.com/tfadeyi/errors"
+)
+
+// packageStats contains the annotation coverage statistics collected while parsing a package
+type packageStats struct {
+	// manifest is the application manifest the package belongs to, its name might only be known once all files are parsed
+	manifest   *api.Manifest
+	defined    map[string]struct{}
+	referenced map[string]struct{}
+	wrapped    int
+	unwrapped  []string
+}
+
+func (p *Parser) packageStats(pkg string) *packageStats {
+	if p.packages == nil {
+		p.packages = map[string]*packageStats{}
+	}
+	s, ok := p.packages[pkg]
+	if !ok {
+		s = &packageStats{
+			defined:    map[string]struct{}{},
+			referenced: map[string]struct{}{},
+		}
+		p.packages[pkg] = s
+	}
+	return s
+}
+
+// analyzeFile collects the error codes referenced in the file and the errors returned by its exported functions
+func (p *Parser) analyzeFile(pkg string, fset *token.FileSet, file *ast.File) {
+	s := p.packageStats(pkg)
+	if s.manifest == nil {
+		s.manifest, _ = p.current.(*api.Manifest)
+	}
+	library := libraryName(file)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if code, ok := wrappedCode(call, library); ok && code != "" {
+				s.referenced[code] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || !fn.Name.IsExported() || !returnsError(fn.Type) {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FuncLit:
+				// returns in closures don't belong to the exported function
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) == 0 {
+					return true
+				}
+				result := unparen(n.Results[len(n.Results)-1])
+				if ident, ok := result.(*ast.Ident); ok && ident.Name == "nil" {
+					return true
+				}
+				if call, ok := result.(*ast.CallExpr); ok {
+					if _, ok := wrappedCode(call, library); ok {
+						s.wrapped++
+						return true
+					}
+				}
+				position := fset.Position(n.Pos())
+				s.unwrapped = append(s.unwrapped, fmt.Sprintf("%s:%d", position.Filename, position.Line))
+			}
+			return true
+		})
+	}
+}
+
+// libraryName returns the name the error.fyi library is imported with in the file, or an empty string
+func libraryName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		value, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || value != libraryImportPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return path.Base(libraryImportPath)
+	}
+	return ""
+}
+
+// wrappedCode returns the error code of calls wrapping an error with a code, i.e: fyi.Error(err, "code"),
+// fyi.ErrorWithContext(ctx, err, "code") or their Wrapper method equivalents.
+func wrappedCode(call *ast.CallExpr, library string) (string, bool) {
+	arg, isLibrary := codeArgument(call, library)
+	if arg == nil {
+		return "", false
+	}
+	if isLibrary {
+		// the code might not be a string literal, the error is still wrapped
+		code, _ := stringLiteral(arg)
+		return code, true
+	}
+	// Wrapper methods, i.e: w.Error(err, "code"), are recognised by their string literal code
+	return stringLiteral(arg)
+}
+
+// codeArgument returns the code argument of calls shaped like fyi.Error(err, code) or fyi.ErrorWithContext(ctx, err, code),
+// and whether the function is called from the error.fyi library rather than a Wrapper. It returns nil for other calls.
+func codeArgument(call *ast.CallExpr, library string) (ast.Expr, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	var arg ast.Expr
+	switch {
+	case selector.Sel.Name == "Error" && len(call.Args) == 2:
+		arg = call.Args[1]
+	case selector.Sel.Name == "ErrorWithContext" && len(call.Args) == 3:
+		arg = call.Args[2]
+	default:
+		return nil, false
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+	return arg, ok && library != "" && ident.Name == library
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func returnsError(fn *ast.FuncType) bool {
+	if fn.Results == nil || len(fn.Results.List) == 0 {
+		return false
+	}
+	last := fn.Results.List[len(fn.Results.List)-1]
+	ident, ok := last.Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// Stats returns the annotation coverage statistics of the parsed source code, it should be called after Parse.
+func (p *Parser) Stats() *stats.Report {
+	report := &stats.Report{}
+	referencedCodes := map[string]struct{}{}
+
+	for pkgPath, s := range p.packages {
+		pkg := &stats.Package{
+			Path:      pkgPath,
+			Unwrapped: s.unwrapped,
+		}
+		if s.manifest != nil {
+			pkg.Application = s.manifest.Name
+		}
+		for code := range s.defined {
+			pkg.Defined = append(pkg.Defined, code)
+		}
+		for code := range s.referenced {
+			pkg.Referenced = append(pkg.Referenced, code)
+			referencedCodes[code] = struct{}{}
+		}
+		pkg.DefinedCodes = len(pkg.Defined)
+		pkg.ReferencedCodes = len(pkg.Referenced)
+		pkg.WrappedReturns = s.wrapped
+		pkg.UnwrappedReturns = len(s.unwrapped)
+		pkg.Coverage = stats.ComputeCoverage(pkg.WrappedReturns, pkg.UnwrappedReturns)
+		report.Packages = append(report.Packages, pkg)
+
+		report.Totals.WrappedReturns += pkg.WrappedReturns
+		report.Totals.UnwrappedReturns += pkg.UnwrappedReturns
+	}
+
+	for _, spec := range p.specs {
+		manifest := spec.(*api.Manifest)
+		app := &stats.Application{Name: manifest.Name}
+		for code, definition := range manifest.ErrorsDefinitions {
+			app.DefinedCodes++
+			if _, ok := referencedCodes[code]; ok {
+				app.ReferencedCodes++
+			} else {
+				app.Unreferenced = append(app.Unreferenced, code)
+			}
+			if definition.Long == nil || *definition.Long == "" {
+				app.MissingLongCodes = append(app.MissingLongCodes, code)
+			}
+			if len(definition.Solutions) == 0 {
+				app.MissingSolutionsCodes = append(app.MissingSolutionsCodes, code)
+			}
+		}
+		app.MissingLong = len(app.MissingLongCodes)
+		app.MissingSolutions = len(app.MissingSolutionsCodes)
+
+		for _, pkg := range report.Packages {
+			if pkg.Application != manifest.Name {
+				continue
+			}
+			app.WrappedReturns += pkg.WrappedReturns
+			app.UnwrappedReturns += pkg.UnwrappedReturns
+		}
+		app.Coverage = stats.ComputeCoverage(app.WrappedReturns, app.UnwrappedReturns)
+		report.Applications = append(report.Applications, app)
+
+		report.Totals.DefinedCodes += app.DefinedCodes
+		report.Totals.ReferencedCodes += app.ReferencedCodes
+		report.Totals.MissingLong += app.MissingLong
+		report.Totals.MissingSolutions += app.MissingSolutions
+	}
+	report.Totals.Coverage = stats.ComputeCoverage(report.Totals.WrappedReturns, report.Totals.UnwrappedReturns)
+
+	report.Sort()
+	return report
+}
+
+// unparen returns the expression with the enclosing parentheses removed
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
@@ -0,0 +1,78 @@
+package golang
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tfadeyi/errors/internal/logging"
+)
+
+func TestParserStats(t *testing.T) {
+	t.Parallel()
+
+	src := `package main
+
+import (
+	"errors"
+
+	fyi "github.com/tfadeyi/errors"
+)
+
+// @fyi name example
+// @fyi base_url https://example.com
+
+// Run returns wrapped and unwrapped errors
+func Run(fail bool) error {
+	err := errors.New("failed")
+	if fail {
+		// @fyi.error code run_failed
+		// @fyi.error title Run Failed
+		// @fyi.error short The run has failed.
+		// @fyi.error long The run has failed, try again.
+		return fyi.Error(err, "run_failed")
+	}
+	go func() error {
+		// returns in closures are ignored
+		return err
+	}()
+	// @fyi.error code run_panicked
+	// @fyi.error title Run Panicked
+	// @fyi.error short The run has panicked.
+	return err
+}
+
+func run() error {
+	return errors.New("unexported functions are ignored")
+}
+`
+	logger := logging.NewStandardLogger()
+	logger = logger.SetLevel("none")
+	p := NewParser(&Options{
+		Logger:        &logger,
+		SourceFile:    "main.go",
+		SourceContent: io.NopCloser(strings.NewReader(src)),
+	})
+	_, err := p.Parse(context.Background())
+	require.NoError(t, err)
+
+	report := p.Stats()
+	require.Len(t, report.Applications, 1)
+	app := report.Applications[0]
+	assert.Equal(t, "example", app.Name)
+	assert.Equal(t, 2, app.DefinedCodes)
+	assert.Equal(t, 1, app.ReferencedCodes)
+	assert.Equal(t, []string{"run_panicked"}, app.Unreferenced)
+	assert.Equal(t, []string{"run_panicked"}, app.MissingLongCodes)
+	assert.Equal(t, []string{"run_failed", "run_panicked"}, app.MissingSolutionsCodes)
+
+	require.Len(t, report.Packages, 1)
+	pkg := report.Packages[0]
+	assert.Equal(t, "example", pkg.Application)
+	assert.Equal(t, 1, pkg.WrappedReturns)
+	assert.Equal(t, []string{"main.go:29"}, pkg.Unwrapped)
+	assert.Equal(t, 50.0, report.Totals.Coverage)
+}
@@ -2,6 +2,9 @@ package language
 
 import (
 	"context"
+
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/stats"
 )
 
 type (
@@ -10,6 +13,24 @@ type (
 		// Parse returns specification(s) struct given a data source, returns error if parsing fails
 		Parse(ctx context.Context) (map[string]any, error)
 	}
+
+	// Analyzer is implemented by the targets able to report the annotation coverage of the parsed source code.
+	Analyzer interface {
+		// Stats returns the annotation coverage statistics of the source code parsed by the last Parse call
+		Stats() *stats.Report
+	}
+
+	// Updater is implemented by the targets able to re-parse only the source files that changed since the last Parse call.
+	Updater interface {
+		// Update returns the specification(s) struct after re-parsing the given changed files
+		Update(ctx context.Context, changed ...string) (map[string]any, error)
+	}
+
+	// Diagnoser is implemented by the targets able to report the problems found in the annotations of the source code.
+	Diagnoser interface {
+		// Diagnostics returns the problems found in the annotations by the last Parse or Update call
+		Diagnostics() diagnostic.List
+	}
 )
 
 const (
@@ -1,6 +1,8 @@
 package options
 
 import (
+	"github.com/tfadeyi/errors/internal/parser/generate/html"
+	"github.com/tfadeyi/errors/internal/parser/generate/json"
 	"github.com/tfadeyi/errors/internal/parser/generate/markdown"
 	"github.com/tfadeyi/errors/internal/parser/generate/yaml"
 	"github.com/tfadeyi/errors/internal/parser/language/golang"
@@ -22,6 +24,21 @@ type (
 		// Option: func Include(dirs ...string) Option
 		IncludedDirs []string
 
+		// ExcludePatterns are the glob patterns of the files and directories, relative to the included directories,
+		// skipped by the parser.
+		// Option: func Exclude(patterns ...string) Option
+		ExcludePatterns []string
+
+		// IncludeTests configures the parser to also parse the _test.go files.
+		// Option: func IncludeTests(include bool) Option
+		IncludeTests bool
+
+		// GOOS, GOARCH and BuildTags are used to evaluate the build constraints of the files, only the files
+		// that would be built for the target platform are parsed. GOOS and GOARCH default to the host platform.
+		// Option: func BuildConstraints(goos, goarch string, tags ...string) Option
+		GOOS, GOARCH string
+		BuildTags    []string
+
 		// Logger is the parser's logger
 		// Option: func Logger(logger *logging.Logger) Option
 		Logger *logging.Logger
@@ -44,8 +61,51 @@ type (
 		// CustomErrorTemplateFilepath path to the custom user template for errors manifest errors
 		CustomErrorTemplateFilepath string
 
+		// TemplateDir is the directory of the templates and assets overriding the default theme of the HTML site.
+		// Option: func TemplateDir(dir string) Option
+		TemplateDir string
+
+		// SourceURL is the repository URL template the generated docs link the errors to their source with,
+		// i.e: https://github.com/org/repo/blob/main/{path}#L{line}.
+		// Option: func SourceURL(template string) Option
+		SourceURL string
+
 		// GenerationWatermark is header sitting at the top of the output file
 		GenerationWatermark string
+
+		// AnnotationPrefixes are the legacy annotation prefixes, i.e: @aloe, accepted by the parser alongside @fyi.
+		// Option: func AnnotationPrefixes(prefixes ...string) Option
+		AnnotationPrefixes []string
+
+		// Concurrency is the maximum number of source files parsed concurrently, it defaults to GOMAXPROCS.
+		// Option: func Concurrency(workers int) Option
+		Concurrency int
+
+		// Analyze configures the parser to parse the whole source files, rather than only their comments,
+		// so the annotation coverage statistics can be collected.
+		// Option: func Analyze() Option
+		Analyze bool
+
+		// CacheDir is the directory the annotations of the parsed files are cached in, the files which didn't change
+		// since the previous run aren't parsed again. The cache is disabled if empty.
+		// Option: func Cache(dir string) Option
+		CacheDir string
+
+		// Fragments are the glob patterns, relative to the included directories, of the YAML or JSON fragment files
+		// completing the errors of the annotations, i.e: errors.d/*.yaml. The default patterns are used if nil.
+		// Option: func Fragments(patterns ...string) Option
+		Fragments []string
+
+		// Merge configures the YAML generator to merge the generated manifest into the existing Output file, so
+		// the fields, comments and key order edited by hand are kept. Orphans is how the errors missing from the
+		// source code are handled, mark (default) or drop.
+		// Option: func Merge(orphans string) Option
+		Merge   bool
+		Orphans string
+
+		// Compact configures the JSON generator to print each manifest on a single line, rather than pretty printing it.
+		// Option: func Compact(compact bool) Option
+		Compact bool
 	}
 	// Option is a more atomic to configure the different Options rather than passing the entire Options struct.
 	Option func(p *Options)
@@ -59,6 +119,30 @@ func Include(dirs ...string) Option {
 	}
 }
 
+// Exclude configure the parser to skip the files and directories matching the given glob patterns
+func Exclude(patterns ...string) Option {
+	return func(e *Options) {
+		e.ExcludePatterns = patterns
+	}
+}
+
+// IncludeTests configure the parser to also parse the _test.go files
+func IncludeTests(include bool) Option {
+	return func(e *Options) {
+		e.IncludeTests = include
+	}
+}
+
+// BuildConstraints configure the platform and build tags used to evaluate the build constraints of the parsed files.
+// Empty goos and goarch default to the host platform.
+func BuildConstraints(goos, goarch string, tags ...string) Option {
+	return func(e *Options) {
+		e.GOOS = goos
+		e.GOARCH = goarch
+		e.BuildTags = tags
+	}
+}
+
 // Logger configure the parser's logger
 func Logger(logger *logging.Logger) Option {
 	return func(e *Options) {
@@ -110,14 +194,93 @@ func CustomManifestErrorTemplate(filepath string) Option {
 	}
 }
 
+// TemplateDir configures the parser's generator to override the default theme of the HTML site with the files of the
+// given directory
+func TemplateDir(dir string) Option {
+	return func(e *Options) {
+		e.TemplateDir = dir
+	}
+}
+
+// SourceURL configures the parser's generator to link the errors to their source using the repository URL template.
+// The {path}, {line} and {column} placeholders are replaced by the location of each error.
+func SourceURL(template string) Option {
+	return func(e *Options) {
+		e.SourceURL = template
+	}
+}
+
+// AnnotationPrefixes configures the parser to also accept annotations using the given legacy prefixes, i.e: @aloe.
+// The legacy annotations are rewritten into the current grammar before being parsed.
+func AnnotationPrefixes(prefixes ...string) Option {
+	return func(e *Options) {
+		e.AnnotationPrefixes = prefixes
+	}
+}
+
+// Concurrency configures the maximum number of source files parsed concurrently, values lower than 1 default to GOMAXPROCS
+func Concurrency(workers int) Option {
+	return func(e *Options) {
+		e.Concurrency = workers
+	}
+}
+
+// Analyze configures the parser to parse the whole source files, so the annotation coverage statistics can be collected
+func Analyze() Option {
+	return func(e *Options) {
+		e.Analyze = true
+	}
+}
+
+// Cache configures the directory the parser caches the annotations of the parsed files in, an empty dir disables the cache
+func Cache(dir string) Option {
+	return func(e *Options) {
+		e.CacheDir = dir
+	}
+}
+
+// Fragments configures the glob patterns, relative to the included directories, of the fragment files merged into
+// the errors of the annotations. No fragment files are read if no patterns are given.
+func Fragments(patterns ...string) Option {
+	return func(e *Options) {
+		e.Fragments = append([]string{}, patterns...)
+	}
+}
+
+// Merge configures the YAML generator to merge the generated manifest into the existing output file, the errors
+// missing from the source code are marked or dropped depending on orphans, i.e: mark.
+func Merge(orphans string) Option {
+	return func(e *Options) {
+		e.Merge = true
+		e.Orphans = orphans
+	}
+}
+
+// Compact configures the JSON generator to print each manifest on a single line, rather than pretty printing it
+func Compact(compact bool) Option {
+	return func(e *Options) {
+		e.Compact = compact
+	}
+}
+
 // Go returns the options.Option to run the parser targeting golang source code
 func Go() Option {
 	return func(opts *Options) {
 		opts.TargetLanguage = golang.NewParser(&golang.Options{
-			Logger:           opts.Logger,
-			SourceFile:       opts.SourceFile,
-			SourceContent:    opts.SourceContent,
-			InputDirectories: opts.IncludedDirs,
+			Logger:             opts.Logger,
+			SourceFile:         opts.SourceFile,
+			SourceContent:      opts.SourceContent,
+			InputDirectories:   opts.IncludedDirs,
+			ExcludePatterns:    opts.ExcludePatterns,
+			IncludeTests:       opts.IncludeTests,
+			GOOS:               opts.GOOS,
+			GOARCH:             opts.GOARCH,
+			BuildTags:          opts.BuildTags,
+			AnnotationPrefixes: opts.AnnotationPrefixes,
+			Concurrency:        opts.Concurrency,
+			Analyze:            opts.Analyze,
+			CacheDir:           opts.CacheDir,
+			Fragments:          opts.Fragments,
 		})
 	}
 }
@@ -126,10 +289,24 @@ func Go() Option {
 func YAML(w io.Writer) Option {
 	return func(opts *Options) {
 		opts.TargetGenerator = yaml.New(&yaml.Options{
-			Logger: opts.Logger,
-			Writer: w,
-			Output: opts.Output,
-			Header: opts.GenerationWatermark,
+			Logger:  opts.Logger,
+			Writer:  w,
+			Output:  opts.Output,
+			Header:  opts.GenerationWatermark,
+			Merge:   opts.Merge,
+			Orphans: opts.Orphans,
+		})
+	}
+}
+
+// JSON returns the options.Option to run the parser generator for JSON
+func JSON(w io.Writer) Option {
+	return func(opts *Options) {
+		opts.TargetGenerator = json.New(&json.Options{
+			Logger:  opts.Logger,
+			Writer:  w,
+			Output:  opts.Output,
+			Compact: opts.Compact,
 		})
 	}
 }
@@ -143,6 +320,20 @@ func Markdown(w io.Writer) Option {
 			Output:        opts.Output,
 			InfoTmplFile:  opts.CustomInfoTemplateFilepath,
 			ErrorTmplFile: opts.CustomErrorTemplateFilepath,
+			SourceURL:     opts.SourceURL,
+		})
+	}
+}
+
+// HTML returns the options.Option to run the parser generator for the HTML static site
+func HTML(w io.Writer) Option {
+	return func(opts *Options) {
+		opts.TargetGenerator = html.New(&html.Options{
+			Logger:      opts.Logger,
+			Writer:      w,
+			Output:      opts.Output,
+			TemplateDir: opts.TemplateDir,
+			SourceURL:   opts.SourceURL,
 		})
 	}
 }
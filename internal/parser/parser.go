@@ -4,7 +4,11 @@ import (
 	"context"
 	"github.com/juju/errors"
 
+	"github.com/tfadeyi/errors/internal/diagnostic"
+	"github.com/tfadeyi/errors/internal/parser/generate"
+	"github.com/tfadeyi/errors/internal/parser/language"
 	"github.com/tfadeyi/errors/internal/parser/options"
+	"github.com/tfadeyi/errors/internal/stats"
 )
 
 type (
@@ -19,6 +23,8 @@ type (
 var (
 	ErrNoContentGenerator = errors.New("no target content generator was set")
 	ErrNoTargetLanguage   = errors.New("no target source language was set")
+	ErrNoStatistics       = errors.New("the target source language doesn't support statistics")
+	ErrNoRenderer         = errors.New("the target content generator can't render its content in memory")
 )
 
 // New creates a new instance of the parser. See options.Option for more info on the available configuration.
@@ -42,9 +48,58 @@ func (p *Parser) Parse(ctx context.Context) (map[string]any, error) {
 	return p.Opts.TargetLanguage.Parse(ctx)
 }
 
+// Update re-parses the files that changed since the last Parse call and returns the updated specification.
+// The whole data source is parsed again if the target language doesn't support incremental parsing.
+func (p *Parser) Update(ctx context.Context, changed ...string) (map[string]any, error) {
+	if p.Opts.TargetLanguage == nil {
+		return nil, ErrNoTargetLanguage
+	}
+	if updater, ok := p.Opts.TargetLanguage.(language.Updater); ok {
+		return updater.Update(ctx, changed...)
+	}
+	return p.Opts.TargetLanguage.Parse(ctx)
+}
+
 func (p *Parser) Generate(ctx context.Context, specs map[string]any) error {
 	if p.Opts.TargetGenerator == nil {
 		return ErrNoContentGenerator
 	}
 	return p.Opts.TargetGenerator.Generate(ctx, specs)
 }
+
+// Check compares the content generated from the specs with the previously generated files on disk, without writing
+// anything. It returns the stale files, see generate.Check.
+func (p *Parser) Check(ctx context.Context, specs map[string]any) ([]generate.StaleFile, error) {
+	if p.Opts.TargetGenerator == nil {
+		return nil, ErrNoContentGenerator
+	}
+	renderer, ok := p.Opts.TargetGenerator.(generate.Renderer)
+	if !ok {
+		return nil, ErrNoRenderer
+	}
+	return generate.Check(ctx, renderer, specs)
+}
+
+// Stats returns the annotation coverage statistics of the source code parsed by Parse.
+func (p *Parser) Stats() (*stats.Report, error) {
+	if p.Opts.TargetLanguage == nil {
+		return nil, ErrNoTargetLanguage
+	}
+	analyzer, ok := p.Opts.TargetLanguage.(language.Analyzer)
+	if !ok {
+		return nil, ErrNoStatistics
+	}
+	return analyzer.Stats(), nil
+}
+
+// Diagnostics returns the problems found in the annotations by the last Parse or Update call.
+// No diagnostics are returned if the target language doesn't report them.
+func (p *Parser) Diagnostics() diagnostic.List {
+	if p.Opts.TargetLanguage == nil {
+		return nil
+	}
+	if diagnoser, ok := p.Opts.TargetLanguage.(language.Diagnoser); ok {
+		return diagnoser.Diagnostics()
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package scaffold sets up the error.fyi library and errctl code generation in an existing go module
+package scaffold
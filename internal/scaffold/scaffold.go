@@ -0,0 +1,594 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/tfadeyi/errors/internal/module"
+	"github.com/tfadeyi/errors/internal/parser/generate/markdown"
+	"github.com/tfadeyi/errors/internal/parser/generate/yaml"
+	"github.com/tfadeyi/errors/pkg/api"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+type (
+	// Scaffolder sets up a go module to use the error.fyi library and errctl
+	Scaffolder struct {
+		logger       *logging.Logger
+		dir          string
+		mainDir      string
+		name         string
+		baseURL      string
+		version      string
+		description  string
+		manifest     string
+		templatesDir string
+		watermark    string
+		force        bool
+	}
+
+	// Options contains the configuration options available to the Scaffolder
+	Options struct {
+		Logger *logging.Logger
+		// Dir is a directory inside the target go module
+		Dir string
+		// MainDir is the directory of the main package, it is detected if left empty
+		MainDir string
+		// Name, BaseURL, Version and Description are the application information added to the main package annotations
+		Name        string
+		BaseURL     string
+		Version     string
+		Description string
+		// Manifest is the filename of the application error manifest created next to the main package
+		Manifest string
+		// TemplatesDir is the directory where the default markdown templates are copied to, leave empty to skip it
+		TemplatesDir string
+		// Watermark is header sitting at the top of the manifest
+		Watermark string
+		// Force allows the scaffolder to overwrite existing files
+		Force bool
+	}
+
+	// Action describes a change made, or skipped, by the scaffolder
+	Action struct {
+		Path   string
+		Status string
+		Reason string
+	}
+)
+
+const (
+	Created = "created"
+	Updated = "updated"
+	Skipped = "skipped"
+
+	// DefaultManifest is the default filename of the application error manifest
+	DefaultManifest = "errors.yaml"
+	// DefaultVersion is the default application version added to the annotations
+	DefaultVersion = "v0.1.0"
+
+	libraryImportPath = "github.com/tfadeyi/errors"
+	libraryImportName = "fyi"
+	manifestVariable  = "errorsManifest"
+	infoTemplateFile  = "info.md.tmpl"
+	errorTemplateFile = "error.md.tmpl"
+)
+
+var (
+	ErrNoMainPackage = errors.New("no main package was found in the go module")
+)
+
+// New creates a new instance of the scaffolder
+func New(opts *Options) *Scaffolder {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+	manifest := opts.Manifest
+	if manifest == "" {
+		manifest = DefaultManifest
+	}
+	version := opts.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	return &Scaffolder{
+		logger:       opts.Logger,
+		dir:          opts.Dir,
+		mainDir:      opts.MainDir,
+		name:         opts.Name,
+		baseURL:      opts.BaseURL,
+		version:      version,
+		description:  opts.Description,
+		manifest:     manifest,
+		templatesDir: opts.TemplatesDir,
+		watermark:    opts.Watermark,
+		force:        opts.Force,
+	}
+}
+
+// Init adds the application annotations, the embedded manifest and the go:generate directive to the main package,
+// creates the initial application error manifest and, optionally, the markdown templates.
+// Existing files are never overwritten unless Force is set.
+func (s *Scaffolder) Init(ctx context.Context) ([]Action, error) {
+	root, err := module.FindRoot(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	modulePath, err := module.Path(root)
+	if err != nil {
+		return nil, err
+	}
+
+	mainDir := s.mainDir
+	if mainDir == "" {
+		mainDir, err = FindMainPackage(root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mainFile, err := findMainFile(mainDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.name == "" {
+		s.name = path.Base(modulePath)
+	}
+	if s.baseURL == "" {
+		s.baseURL = defaultBaseURL(modulePath)
+	}
+	if s.baseURL == "" {
+		return nil, errors.Errorf("no base URL could be derived from the module path %q", modulePath)
+	}
+
+	var actions []Action
+
+	action, err := s.writeManifest(ctx, filepath.Join(mainDir, s.manifest))
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, action)
+
+	if s.templatesDir != "" {
+		templateActions, err := s.writeTemplates()
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, templateActions...)
+	}
+
+	action, err = s.updateMainFile(mainFile)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, action)
+
+	return actions, nil
+}
+
+// defaultBaseURL returns the GitHub pages URL of the module, if the module is hosted on GitHub
+func defaultBaseURL(modulePath string) string {
+	parts := strings.Split(modulePath, "/")
+	if len(parts) < 2 || parts[0] != "github.com" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s.github.io", parts[1])
+}
+
+// FindMainPackage returns the directory of the main package closest to the root directory.
+// Hidden, vendor and testdata directories are skipped.
+func FindMainPackage(root string) (string, error) {
+	var candidates []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if path != root {
+			// nested modules are not part of the target module
+			if _, err := os.Stat(filepath.Join(path, module.GoModFilename)); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		if _, err := findMainFile(path); err == nil {
+			candidates = append(candidates, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", ErrNoMainPackage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		di := strings.Count(candidates[i], string(filepath.Separator))
+		dj := strings.Count(candidates[j], string(filepath.Separator))
+		if di != dj {
+			return di < dj
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates[0], nil
+}
+
+// findMainFile returns the file declaring the main function of the main package in the given directory
+func findMainFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		file, err := goparser.ParseFile(token.NewFileSet(), filename, nil, goparser.SkipObjectResolution)
+		if err != nil || file.Name.Name != "main" {
+			continue
+		}
+		if findMainFunc(file) != nil {
+			return filename, nil
+		}
+	}
+	return "", ErrNoMainPackage
+}
+
+func findMainFunc(file *ast.File) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv == nil && fn.Name.Name == "main" && fn.Body != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+func (s *Scaffolder) writeManifest(ctx context.Context, filename string) (Action, error) {
+	if action, ok := s.skipExisting(filename); ok {
+		return action, nil
+	}
+
+	description := s.description
+	manifest := &api.Manifest{
+		BaseUrl:           s.baseURL,
+		ErrorsDefinitions: api.ErrorDefinitions{},
+		Name:              s.name,
+		Version:           s.version,
+	}
+	if description != "" {
+		manifest.Description = &description
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	generator := yaml.New(&yaml.Options{
+		Logger: s.logger,
+		Writer: buf,
+		Header: s.watermark,
+	})
+	if err := generator.Generate(ctx, map[string]any{manifest.Name: manifest}); err != nil {
+		return Action{}, err
+	}
+	return s.writeFile(filename, buf.Bytes())
+}
+
+func (s *Scaffolder) writeTemplates() ([]Action, error) {
+	templates := map[string]string{
+		filepath.Join(s.templatesDir, infoTemplateFile):  markdown.DefaultInfoTemplate(),
+		filepath.Join(s.templatesDir, errorTemplateFile): markdown.DefaultErrorTemplate(),
+	}
+
+	filenames := make([]string, 0, len(templates))
+	for filename := range templates {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	var actions []Action
+	for _, filename := range filenames {
+		if action, ok := s.skipExisting(filename); ok {
+			actions = append(actions, action)
+			continue
+		}
+		action, err := s.writeFile(filename, []byte(templates[filename]))
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// skipExisting returns a skipped action if the file exists and the scaffolder is not allowed to overwrite it
+func (s *Scaffolder) skipExisting(filename string) (Action, bool) {
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) || s.force {
+		return Action{}, false
+	}
+	return Action{Path: filename, Status: Skipped, Reason: "file already exists, use --force to overwrite it"}, true
+}
+
+func (s *Scaffolder) writeFile(filename string, body []byte) (Action, error) {
+	status := Created
+	if _, err := os.Stat(filename); err == nil {
+		status = Updated
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return Action{}, err
+	}
+	if err := os.WriteFile(filename, body, 0644); err != nil {
+		return Action{}, err
+	}
+	return Action{Path: filename, Status: status}, nil
+}
+
+type insertion struct {
+	offset int
+	text   string
+}
+
+// updateMainFile adds the missing boilerplate to the file declaring the main function.
+// Each step is skipped if the file already contains it, so running it more than once leaves the file unchanged.
+func (s *Scaffolder) updateMainFile(filename string) (Action, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return Action{}, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, filename, src, goparser.ParseComments)
+	if err != nil {
+		return Action{}, err
+	}
+	mainFunc := findMainFunc(file)
+	if mainFunc == nil {
+		return Action{}, ErrNoMainPackage
+	}
+	// the boilerplate can be in any file of the main package, i.e: the annotations in doc.go
+	files, err := packageFiles(filename, file)
+	if err != nil {
+		return Action{}, err
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	libraryName := importName(file, libraryImportPath)
+	manifestVar := embeddedVariable(file, s.manifest)
+	var insertions []insertion
+
+	if !hasComment(files, "//go:generate", "errctl generate") {
+		directive := fmt.Sprintf("//go:generate errctl generate -o %s --log-level none\n", s.manifest)
+		if s.templatesDir != "" {
+			// go:generate runs the command from the main package directory
+			templatesDir, err := relativeTo(filepath.Dir(filename), s.templatesDir)
+			if err != nil {
+				return Action{}, err
+			}
+			directive += fmt.Sprintf("//go:generate errctl generate --format markdown -o docs --info-template %s --error-template %s --log-level none\n",
+				filepath.ToSlash(filepath.Join(templatesDir, infoTemplateFile)),
+				filepath.ToSlash(filepath.Join(templatesDir, errorTemplateFile)),
+			)
+		}
+		insertions = append(insertions, insertion{offset: 0, text: directive + "\n"})
+	}
+
+	declarationPos := mainFunc.Pos()
+	if mainFunc.Doc != nil {
+		declarationPos = mainFunc.Doc.Pos()
+	}
+	// insertions at the same offset are applied in reverse order, the embedded manifest goes above the annotations
+	if !hasComment(files, "// @fyi name") {
+		annotations := []string{
+			fmt.Sprintf("// @fyi name %s", s.name),
+			fmt.Sprintf("// @fyi base_url %s", s.baseURL),
+			fmt.Sprintf("// @fyi version %s", s.version),
+		}
+		if s.description != "" {
+			annotations = append(annotations, fmt.Sprintf("// @fyi description %s", s.description))
+		}
+		insertions = append(insertions, insertion{
+			offset: offset(declarationPos),
+			text:   strings.Join(annotations, "\n") + "\n\n",
+		})
+	}
+	if manifestVar == "" {
+		manifestVar = manifestVariable
+		insertions = append(insertions, insertion{
+			offset: offset(declarationPos),
+			text:   fmt.Sprintf("//go:embed %s\nvar %s []byte\n\n", s.manifest, manifestVar),
+		})
+	}
+	if libraryName == "" || !callsSetManifest(mainFunc, libraryName) {
+		name := libraryName
+		if name == "" {
+			name = libraryImportName
+		}
+		insertions = append(insertions, insertion{
+			offset: offset(mainFunc.Body.Lbrace) + 1,
+			text:   fmt.Sprintf("\n%s.SetManifest(%s)", name, manifestVar),
+		})
+	}
+
+	if len(insertions) == 0 {
+		return Action{Path: filename, Status: Skipped, Reason: "already initialised"}, nil
+	}
+
+	// apply the insertions from the end of the file, so the offsets stay valid
+	sort.SliceStable(insertions, func(i, j int) bool {
+		return insertions[i].offset > insertions[j].offset
+	})
+	updated := append([]byte{}, src...)
+	for _, ins := range insertions {
+		updated = append(updated[:ins.offset], append([]byte(ins.text), updated[ins.offset:]...)...)
+	}
+
+	updated, err = addImports(filename, updated, libraryName == "")
+	if err != nil {
+		return Action{}, err
+	}
+	if err := os.WriteFile(filename, updated, 0644); err != nil {
+		return Action{}, err
+	}
+	return Action{Path: filename, Status: Updated}, nil
+}
+
+// relativeTo returns the target path relative to the base directory
+func relativeTo(base, target string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absBase, absTarget)
+}
+
+// addImports adds the embed and, if requested, the library imports then formats the source
+func addImports(filename string, src []byte, library bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, filename, src, goparser.ParseComments)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to parse the updated file %q", filename)
+	}
+	if importName(file, "embed") == "" {
+		astutil.AddNamedImport(fset, file, "_", "embed")
+	}
+	if library {
+		astutil.AddNamedImport(fset, file, libraryImportName, libraryImportPath)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := format.Node(buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importName returns the name the import path is referenced with in the file, or an empty string if it isn't imported
+func importName(file *ast.File, importPath string) string {
+	for _, spec := range file.Imports {
+		value, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || value != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return path.Base(importPath)
+	}
+	return ""
+}
+
+// embeddedVariable returns the name of the variable embedding the given file
+func embeddedVariable(file *ast.File, filename string) string {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok || len(value.Names) == 0 {
+				continue
+			}
+			for _, doc := range []*ast.CommentGroup{gen.Doc, value.Doc} {
+				if doc == nil {
+					continue
+				}
+				for _, comment := range doc.List {
+					if strings.TrimSpace(comment.Text) == "//go:embed "+filename {
+						return value.Names[0].Name
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// packageFiles returns the parsed files of the package of the given file, the file included, test files excluded
+func packageFiles(filename string, file *ast.File) ([]*ast.File, error) {
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []*ast.File{file}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" || strings.HasSuffix(entry.Name(), "_test.go") ||
+			entry.Name() == filepath.Base(filename) {
+			continue
+		}
+		sibling, err := goparser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, goparser.ParseComments|goparser.SkipObjectResolution)
+		if err != nil || sibling.Name.Name != file.Name.Name {
+			continue
+		}
+		files = append(files, sibling)
+	}
+	return files, nil
+}
+
+// hasComment returns true if one of the files has a comment starting with prefix and containing all the given substrings
+func hasComment(files []*ast.File, prefix string, contains ...string) bool {
+	for _, file := range files {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if !strings.HasPrefix(comment.Text, prefix) {
+					continue
+				}
+				found := true
+				for _, sub := range contains {
+					found = found && strings.Contains(comment.Text, sub)
+				}
+				if found {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func callsSetManifest(fn *ast.FuncDecl, libraryName string) bool {
+	found := false
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return !found
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == libraryName &&
+			(selector.Sel.Name == "SetManifest" || selector.Sel.Name == "SetManifestFilename") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,120 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/acme/widget\n\ngo 1.20\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "cmd", "widget"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cmd", "widget", "main.go"), []byte(`package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("widget")
+}
+`), 0644))
+	return root
+}
+
+func TestScaffolder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Successfully initialise the main package of the module", func(t *testing.T) {
+		root := newModule(t)
+		actions, err := New(&Options{Dir: root}).Init(context.Background())
+		require.NoError(t, err)
+		require.Len(t, actions, 2)
+
+		mainDir := filepath.Join(root, "cmd", "widget")
+		assert.Equal(t, Action{Path: filepath.Join(mainDir, DefaultManifest), Status: Created}, actions[0])
+		assert.Equal(t, Action{Path: filepath.Join(mainDir, "main.go"), Status: Updated}, actions[1])
+
+		manifest, err := os.ReadFile(filepath.Join(mainDir, DefaultManifest))
+		require.NoError(t, err)
+		assert.Contains(t, string(manifest), "name: widget")
+		assert.Contains(t, string(manifest), "base_url: https://acme.github.io")
+
+		main, err := os.ReadFile(filepath.Join(mainDir, "main.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(main), "//go:generate errctl generate -o errors.yaml")
+		assert.Contains(t, string(main), `_ "embed"`)
+		assert.Contains(t, string(main), `fyi "github.com/tfadeyi/errors"`)
+		assert.Contains(t, string(main), "//go:embed errors.yaml\nvar errorsManifest []byte")
+		assert.Contains(t, string(main), "// @fyi name widget\n// @fyi base_url https://acme.github.io\n// @fyi version v0.1.0")
+		assert.Contains(t, string(main), "func main() {\n\tfyi.SetManifest(errorsManifest)\n\tfmt.Println(\"widget\")")
+	})
+	t.Run("Successfully leave an initialised module unchanged", func(t *testing.T) {
+		root := newModule(t)
+		_, err := New(&Options{Dir: root}).Init(context.Background())
+		require.NoError(t, err)
+
+		mainFile := filepath.Join(root, "cmd", "widget", "main.go")
+		before, err := os.ReadFile(mainFile)
+		require.NoError(t, err)
+
+		actions, err := New(&Options{Dir: root, Name: "other"}).Init(context.Background())
+		require.NoError(t, err)
+		for _, action := range actions {
+			assert.Equal(t, Skipped, action.Status, action.Path)
+		}
+
+		after, err := os.ReadFile(mainFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+	t.Run("Successfully keep the annotations declared in another file of the main package", func(t *testing.T) {
+		root := newModule(t)
+		mainDir := filepath.Join(root, "cmd", "widget")
+		require.NoError(t, os.WriteFile(filepath.Join(mainDir, "doc.go"), []byte(`//go:generate errctl generate -o errors.yaml
+
+// @fyi name widget
+// @fyi base_url https://acme.github.io
+// @fyi version v1.0.0
+package main
+`), 0644))
+
+		_, err := New(&Options{Dir: root}).Init(context.Background())
+		require.NoError(t, err)
+
+		main, err := os.ReadFile(filepath.Join(mainDir, "main.go"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(main), "@fyi name")
+		assert.NotContains(t, string(main), "//go:generate")
+		assert.Contains(t, string(main), "fyi.SetManifest(errorsManifest)")
+	})
+	t.Run("Successfully overwrite the existing files only when forced", func(t *testing.T) {
+		root := newModule(t)
+		templates := filepath.Join(root, "templates")
+		require.NoError(t, os.MkdirAll(templates, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(templates, infoTemplateFile), []byte("custom"), 0644))
+
+		actions, err := New(&Options{Dir: root, TemplatesDir: templates}).Init(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, actions, Action{Path: filepath.Join(templates, errorTemplateFile), Status: Created})
+		body, err := os.ReadFile(filepath.Join(templates, infoTemplateFile))
+		require.NoError(t, err)
+		assert.Equal(t, "custom", string(body))
+
+		_, err = New(&Options{Dir: root, TemplatesDir: templates, Force: true}).Init(context.Background())
+		require.NoError(t, err)
+		body, err = os.ReadFile(filepath.Join(templates, infoTemplateFile))
+		require.NoError(t, err)
+		assert.NotEqual(t, "custom", string(body))
+	})
+	t.Run("Fail to initialise a module without main package", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/acme/lib\n"), 0644))
+		_, err := New(&Options{Dir: root}).Init(context.Background())
+		assert.ErrorIs(t, err, ErrNoMainPackage)
+	})
+}
@@ -0,0 +1,2 @@
+// Package server serves a local preview of the generated markdown documentation as HTML
+package server
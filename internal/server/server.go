@@ -0,0 +1,324 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/tfadeyi/errors/internal/logging"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+//go:embed templates/page.html.tmpl
+var pageHTMLTmpl string
+
+var pageTemplate = template.Must(template.New("page").Parse(pageHTMLTmpl))
+
+type (
+	// Server serves the HTML version of the markdown documentation files returned by its Build function
+	Server struct {
+		logger     *logging.Logger
+		address    string
+		build      func(ctx context.Context) (map[string][]byte, error)
+		liveReload bool
+
+		mu       sync.RWMutex
+		pages    map[string][]byte
+		buildErr error
+		clients  map[chan struct{}]struct{}
+	}
+
+	// Options contains the configuration options available to the Server
+	Options struct {
+		Logger *logging.Logger
+		// Address is the TCP address the server listens on, i.e: localhost:3000
+		Address string
+		// Build returns the markdown documentation files keyed by their path, relative to the site root,
+		// i.e: {name}/index.md and {name}/errors/{code}.md
+		Build func(ctx context.Context) (map[string][]byte, error)
+		// LiveReload enables the browser reload script in the served pages
+		LiveReload bool
+	}
+
+	page struct {
+		Title      string
+		Body       template.HTML
+		LiveReload bool
+		ReloadPath string
+	}
+)
+
+const (
+	// DefaultAddress is the address the server listens on if none is given
+	DefaultAddress = "localhost:3000"
+
+	reloadPath = "/_errctl/reload"
+)
+
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// New creates a new instance of the documentation server
+func New(opts *Options) *Server {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+	address := opts.Address
+	if address == "" {
+		address = DefaultAddress
+	}
+
+	return &Server{
+		logger:     opts.Logger,
+		address:    address,
+		build:      opts.Build,
+		liveReload: opts.LiveReload,
+		pages:      map[string][]byte{},
+		clients:    map[chan struct{}]struct{}{},
+	}
+}
+
+// Rebuild regenerates the served pages and notifies the connected browsers.
+// If the build fails, the previously built pages keep being served.
+func (s *Server) Rebuild(ctx context.Context) error {
+	if s.build == nil {
+		return errors.New("no documentation build function was set")
+	}
+
+	files, err := s.build(ctx)
+	if err == nil {
+		var pages map[string][]byte
+		pages, err = s.renderPages(files)
+		if err == nil {
+			s.mu.Lock()
+			s.pages = pages
+			s.buildErr = nil
+			s.mu.Unlock()
+			s.notify()
+			return nil
+		}
+	}
+
+	s.mu.Lock()
+	s.buildErr = err
+	s.mu.Unlock()
+	return err
+}
+
+func (s *Server) renderPages(files map[string][]byte) (map[string][]byte, error) {
+	pages := make(map[string][]byte, len(files)+1)
+	applications := map[string]struct{}{}
+
+	for path, body := range files {
+		path = filepath.ToSlash(path)
+		if !strings.HasSuffix(path, ".md") {
+			continue
+		}
+
+		url := "/" + strings.TrimSuffix(path, ".md")
+		if strings.HasSuffix(url, "/index") {
+			url = strings.TrimSuffix(url, "index")
+			applications[strings.Trim(url, "/")] = struct{}{}
+		}
+
+		html, err := s.renderMarkdown(body)
+		if err != nil {
+			return nil, errors.Annotatef(err, "could not render %q", path)
+		}
+		pages[url] = html
+	}
+
+	if _, ok := pages["/"]; !ok {
+		html, err := s.renderApplicationsIndex(applications)
+		if err != nil {
+			return nil, err
+		}
+		pages["/"] = html
+	}
+	return pages, nil
+}
+
+// renderMarkdown renders the markdown document as an HTML page. The documents contain the annotations text as written
+// in the source code, the rendered HTML is sanitized so the markup injected by the annotations doesn't reach the page.
+func (s *Server) renderMarkdown(body []byte) ([]byte, error) {
+	title, content := splitFrontMatter(body)
+
+	html := bytes.NewBuffer([]byte{})
+	if err := markdownRenderer.Convert(content, html); err != nil {
+		return nil, err
+	}
+	return s.renderPage(title, template.HTML(bluemonday.UGCPolicy().SanitizeBytes(html.Bytes())))
+}
+
+func (s *Server) renderApplicationsIndex(applications map[string]struct{}) ([]byte, error) {
+	names := make([]string, 0, len(applications))
+	for name := range applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	body := bytes.NewBufferString("<h2>Applications</h2>\n<ul>\n")
+	for _, name := range names {
+		escaped := template.HTMLEscapeString(name)
+		fmt.Fprintf(body, "  <li><a href=\"/%s/\">%s</a></li>\n", escaped, escaped)
+	}
+	body.WriteString("</ul>\n")
+	return s.renderPage("Applications", template.HTML(body.String()))
+}
+
+func (s *Server) renderPage(title string, body template.HTML) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	err := pageTemplate.Execute(buf, page{
+		Title:      title,
+		Body:       body,
+		LiveReload: s.liveReload,
+		ReloadPath: reloadPath,
+	})
+	return buf.Bytes(), err
+}
+
+// splitFrontMatter removes the YAML front matter from the markdown document, returning the front matter title
+func splitFrontMatter(body []byte) (string, []byte) {
+	const delimiter = "---"
+	content := bytes.TrimLeft(body, "\n\r\t ")
+	if !bytes.HasPrefix(content, []byte(delimiter)) {
+		return "", body
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != delimiter {
+			continue
+		}
+		var title string
+		for _, line := range lines[1:i] {
+			if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "title" {
+				title = strings.TrimSpace(value)
+			}
+		}
+		return title, []byte(strings.Join(lines[i+1:], "\n"))
+	}
+	return "", body
+}
+
+// ServeHTTP serves the rendered pages and the live reload event stream
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == reloadPath && s.liveReload {
+		s.serveReloadEvents(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.buildErr != nil && len(s.pages) == 0 {
+		http.Error(w, s.buildErr.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	path := r.URL.Path
+	body, ok := s.pages[path]
+	if !ok {
+		// application indexes are served from their directory, so relative links resolve
+		if _, found := s.pages[path+"/"]; found {
+			http.Redirect(w, r, path+"/", http.StatusMovedPermanently)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+func (s *Server) serveReloadEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	reload := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[reload] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, reload)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-reload:
+			if _, err := fmt.Fprint(w, "data: reload\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// notify tells the connected browsers to reload the current page
+func (s *Server) notify() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for client := range s.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ListenAndServe serves the documentation until the context is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return errors.Annotatef(err, "could not listen on %q", s.address)
+	}
+
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if s.logger != nil {
+		s.logger.Info("Serving the documentation 📖", "url", fmt.Sprintf("http://%s", listener.Addr().String()))
+	}
+
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	build := func(ctx context.Context) (map[string][]byte, error) {
+		return map[string][]byte{
+			"cli/index.md": []byte(`---
+title: cli
+---
+
+## cli
+`),
+			"cli/errors/invalid_flag.md": []byte(`---
+title: Invalid Flag
+code: invalid_flag
+---
+
+## Invalid Flag
+`),
+		}, nil
+	}
+
+	t.Run("Successfully serve the application index and error pages as HTML", func(t *testing.T) {
+		srv := New(&Options{Build: build})
+		require.NoError(t, srv.Rebuild(context.Background()))
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cli/errors/invalid_flag", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<title>Invalid Flag</title>")
+		assert.Contains(t, rec.Body.String(), "<h2>Invalid Flag</h2>")
+		assert.NotContains(t, rec.Body.String(), "code: invalid_flag")
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Contains(t, rec.Body.String(), `<a href="/cli/">cli</a>`)
+	})
+	t.Run("Successfully remove the markup injected in the documentation", func(t *testing.T) {
+		srv := New(&Options{Build: func(ctx context.Context) (map[string][]byte, error) {
+			return map[string][]byte{
+				"cli/errors/bad.md": []byte(`---
+title: <script>alert(1)</script>
+---
+
+## Bad
+
+<script>alert(2)</script>
+
+Use <img src=x onerror=alert(3)> and [this](javascript:alert(4)) link.
+`),
+			}, nil
+		}})
+		require.NoError(t, srv.Rebuild(context.Background()))
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cli/errors/bad", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<h2>Bad</h2>")
+		assert.NotContains(t, rec.Body.String(), "<script>alert")
+		assert.NotContains(t, rec.Body.String(), "onerror")
+		assert.NotContains(t, rec.Body.String(), "javascript:")
+	})
+	t.Run("Successfully redirect to the application index directory", func(t *testing.T) {
+		srv := New(&Options{Build: build})
+		require.NoError(t, srv.Rebuild(context.Background()))
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cli", nil))
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "/cli/", rec.Header().Get("Location"))
+	})
+	t.Run("Successfully inject the live reload script only when enabled", func(t *testing.T) {
+		srv := New(&Options{Build: build, LiveReload: true})
+		require.NoError(t, srv.Rebuild(context.Background()))
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cli/", nil))
+		assert.Contains(t, rec.Body.String(), "EventSource")
+
+		srv = New(&Options{Build: build})
+		require.NoError(t, srv.Rebuild(context.Background()))
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cli/", nil))
+		assert.NotContains(t, rec.Body.String(), "EventSource")
+	})
+}
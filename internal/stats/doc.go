@@ -0,0 +1,2 @@
+// Package stats reports the annotation coverage statistics of the parsed source code
+package stats
@@ -0,0 +1,207 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type (
+	// Report contains the annotation coverage statistics of the parsed source code
+	Report struct {
+		Applications []*Application `json:"applications"`
+		Packages     []*Package     `json:"packages"`
+		Totals       Summary        `json:"totals"`
+	}
+
+	// Summary contains the counters shared by the packages, applications and totals
+	Summary struct {
+		// DefinedCodes is the number of error codes defined through annotations
+		DefinedCodes int `json:"defined_codes"`
+		// ReferencedCodes is the number of distinct error codes wrapped around errors in the code, i.e: fyi.Error(err, "code")
+		ReferencedCodes int `json:"referenced_codes"`
+		// WrappedReturns is the number of errors returned by exported functions wrapped with an error code
+		WrappedReturns int `json:"wrapped_returns"`
+		// UnwrappedReturns is the number of errors returned by exported functions without an error code, i.e: return err
+		UnwrappedReturns int `json:"unwrapped_returns"`
+		// MissingLong is the number of error definitions without a long description
+		MissingLong int `json:"missing_long"`
+		// MissingSolutions is the number of error definitions without solutions
+		MissingSolutions int `json:"missing_solutions"`
+		// Coverage is the percentage of errors returned by exported functions wrapped with an error code
+		Coverage float64 `json:"coverage"`
+	}
+
+	// Package contains the statistics of a single go package
+	Package struct {
+		Path        string `json:"path"`
+		Application string `json:"application,omitempty"`
+		Summary
+		Defined    []string `json:"defined,omitempty"`
+		Referenced []string `json:"referenced,omitempty"`
+		// Unwrapped contains the locations, file:line, of the errors returned without an error code
+		Unwrapped []string `json:"unwrapped,omitempty"`
+	}
+
+	// Application contains the statistics of an application error manifest
+	Application struct {
+		Name string `json:"name"`
+		Summary
+		// Unreferenced contains the defined codes never wrapped around an error in the code
+		Unreferenced          []string `json:"unreferenced,omitempty"`
+		MissingLongCodes      []string `json:"missing_long_codes,omitempty"`
+		MissingSolutionsCodes []string `json:"missing_solutions_codes,omitempty"`
+	}
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+	Badge = "badge"
+)
+
+// IsSupportedFormat checks if the given report format is a supported one
+func IsSupportedFormat(format string) bool {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case Table, JSON, Badge:
+		return true
+	}
+	return false
+}
+
+// ComputeCoverage returns the percentage of wrapped error returns, rounded to one decimal place.
+// Code without error returns is considered fully covered.
+func ComputeCoverage(wrapped, unwrapped int) float64 {
+	total := wrapped + unwrapped
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(wrapped)/float64(total)*1000) / 10
+}
+
+// Sort orders the applications and packages by name, so the report output is stable
+func (r *Report) Sort() {
+	sort.Slice(r.Applications, func(i, j int) bool {
+		return r.Applications[i].Name < r.Applications[j].Name
+	})
+	sort.Slice(r.Packages, func(i, j int) bool {
+		return r.Packages[i].Path < r.Packages[j].Path
+	})
+	for _, pkg := range r.Packages {
+		sort.Strings(pkg.Defined)
+		sort.Strings(pkg.Referenced)
+		sort.Strings(pkg.Unwrapped)
+	}
+	for _, app := range r.Applications {
+		sort.Strings(app.Unreferenced)
+		sort.Strings(app.MissingLongCodes)
+		sort.Strings(app.MissingSolutionsCodes)
+	}
+}
+
+// Write writes the report to the writer in the given format (table, json, badge)
+func (r *Report) Write(w io.Writer, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case JSON:
+		return r.writeJSON(w)
+	case Badge:
+		return r.writeBadge(w)
+	default:
+		return r.writeTable(w)
+	}
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func (r *Report) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "APPLICATION\tDEFINED\tREFERENCED\tWRAPPED\tUNWRAPPED\tMISSING LONG\tMISSING SOLUTIONS\tCOVERAGE")
+	for _, app := range r.Applications {
+		fmt.Fprintf(tw, "%s\t%s\n", app.Name, app.Summary.columns())
+	}
+	fmt.Fprintf(tw, "%s\t%s\n", "TOTAL", r.Totals.columns())
+	fmt.Fprintln(tw, "\t\t\t\t\t\t\t")
+
+	fmt.Fprintln(tw, "PACKAGE\tDEFINED\tREFERENCED\tWRAPPED\tUNWRAPPED\tMISSING LONG\tMISSING SOLUTIONS\tCOVERAGE")
+	for _, pkg := range r.Packages {
+		fmt.Fprintf(tw, "%s\t%s\n", pkg.Path, pkg.Summary.columns())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, app := range r.Applications {
+		if len(app.MissingLongCodes) > 0 {
+			fmt.Fprintf(w, "\n%s: definitions missing a long description: %s\n", app.Name, strings.Join(app.MissingLongCodes, ", "))
+		}
+		if len(app.MissingSolutionsCodes) > 0 {
+			fmt.Fprintf(w, "\n%s: definitions missing solutions: %s\n", app.Name, strings.Join(app.MissingSolutionsCodes, ", "))
+		}
+	}
+
+	var unwrapped []string
+	for _, pkg := range r.Packages {
+		unwrapped = append(unwrapped, pkg.Unwrapped...)
+	}
+	if len(unwrapped) > 0 {
+		fmt.Fprintf(w, "\nerrors returned without an error code:\n")
+		for _, location := range unwrapped {
+			fmt.Fprintf(w, "  %s\n", location)
+		}
+	}
+	return nil
+}
+
+func (s Summary) columns() string {
+	return fmt.Sprintf("%d\t%d\t%d\t%d\t%d\t%d\t%.1f%%",
+		s.DefinedCodes, s.ReferencedCodes, s.WrappedReturns, s.UnwrappedReturns, s.MissingLong, s.MissingSolutions, s.Coverage)
+}
+
+// writeBadge writes the total coverage as a shields.io style SVG badge
+func (r *Report) writeBadge(w io.Writer) error {
+	const (
+		label      = "error docs"
+		labelWidth = 70
+		valueWidth = 50
+	)
+	value := fmt.Sprintf("%.0f%%", r.Totals.Coverage)
+	width := labelWidth + valueWidth
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+  <title>%s: %s</title>
+  <rect width="%d" height="20" fill="#555"/>
+  <rect x="%d" width="%d" height="20" fill="%s"/>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%d" y="14">%s</text>
+    <text x="%d" y="14">%s</text>
+  </g>
+</svg>
+`, width, label, value, label, value,
+		labelWidth, labelWidth, valueWidth, badgeColor(r.Totals.Coverage),
+		labelWidth/2, label, labelWidth+valueWidth/2, value)
+	return err
+}
+
+func badgeColor(coverage float64) string {
+	switch {
+	case coverage >= 90:
+		return "#4c1"
+	case coverage >= 75:
+		return "#97ca00"
+	case coverage >= 50:
+		return "#dfb317"
+	case coverage >= 25:
+		return "#fe7d37"
+	default:
+		return "#e05d44"
+	}
+}
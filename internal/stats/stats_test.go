@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCoverage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wrapped, unwrapped int
+		coverage           float64
+	}{
+		{0, 0, 100},
+		{5, 0, 100},
+		{0, 5, 0},
+		{1, 1, 50},
+		{1, 2, 33.3},
+		{2, 1, 66.7},
+		{1, 7, 12.5},
+		{999, 1, 99.9},
+		{1999, 1, 100},
+		{1, 1999, 0.1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.coverage, ComputeCoverage(tt.wrapped, tt.unwrapped), "wrapped %d, unwrapped %d", tt.wrapped, tt.unwrapped)
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	t.Parallel()
+
+	summary := Summary{DefinedCodes: 2, ReferencedCodes: 1, WrappedReturns: 1, UnwrappedReturns: 2, MissingLong: 1, MissingSolutions: 2, Coverage: ComputeCoverage(1, 2)}
+	report := func(coverage float64) *Report {
+		totals := summary
+		totals.Coverage = coverage
+		return &Report{
+			Applications: []*Application{{
+				Name:                  "cli",
+				Summary:               summary,
+				MissingLongCodes:      []string{"invalid_flag"},
+				MissingSolutionsCodes: []string{"invalid_flag", "timeout"},
+			}},
+			Packages: []*Package{{
+				Path:        "example.com/cli/cmd",
+				Application: "cli",
+				Summary:     summary,
+				Unwrapped:   []string{"cmd/root.go:12", "cmd/run.go:40"},
+			}},
+			Totals: totals,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		coverage float64
+		contains []string
+	}{
+		{
+			name:     "table",
+			format:   Table,
+			coverage: summary.Coverage,
+			contains: []string{
+				"APPLICATION          DEFINED  REFERENCED  WRAPPED  UNWRAPPED  MISSING LONG  MISSING SOLUTIONS  COVERAGE\n",
+				"cli                  2        1           1        2          1             2                  33.3%\n",
+				"TOTAL                2        1           1        2          1             2                  33.3%\n",
+				"example.com/cli/cmd  2        1           1        2          1             2                  33.3%\n",
+				"\ncli: definitions missing a long description: invalid_flag\n",
+				"\ncli: definitions missing solutions: invalid_flag, timeout\n",
+				"\nerrors returned without an error code:\n  cmd/root.go:12\n  cmd/run.go:40\n",
+			},
+		},
+		{
+			name:     "default to the table",
+			format:   "",
+			coverage: summary.Coverage,
+			contains: []string{"TOTAL                2"},
+		},
+		{
+			name:     "json",
+			format:   " JSON ",
+			coverage: summary.Coverage,
+			contains: []string{"{\n  \"applications\": [\n", "\"coverage\": 33.3", "\"unwrapped\": [\n        \"cmd/root.go:12\","},
+		},
+		{
+			name:     "badge",
+			format:   Badge,
+			coverage: summary.Coverage,
+			contains: []string{`aria-label="error docs: 33%"`, `<rect x="70" width="50" height="20" fill="#fe7d37"/>`, `<text x="95" y="14">33%</text>`},
+		},
+		{
+			name:     "badge of full coverage",
+			format:   Badge,
+			coverage: 100,
+			contains: []string{`<title>error docs: 100%</title>`, `fill="#4c1"`},
+		},
+		{
+			name:     "badge of rounded coverage",
+			format:   Badge,
+			coverage: 89.6,
+			contains: []string{`<title>error docs: 90%</title>`, `fill="#97ca00"`},
+		},
+		{
+			name:     "badge of low coverage",
+			format:   Badge,
+			coverage: 12.5,
+			contains: []string{`<title>error docs: 12%</title>`, `fill="#e05d44"`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run("Successfully write the "+tt.name+" report", func(t *testing.T) {
+			t.Parallel()
+			buf := bytes.NewBuffer([]byte{})
+			require.NoError(t, report(tt.coverage).Write(buf, tt.format))
+			for _, expected := range tt.contains {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+
+	t.Run("Successfully write the json report decoding to the same report", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer([]byte{})
+		require.NoError(t, report(summary.Coverage).Write(buf, JSON))
+		decoded := new(Report)
+		require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+		assert.Equal(t, report(summary.Coverage), decoded)
+	})
+}
+
+func TestIsSupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format    string
+		supported bool
+	}{
+		{Table, true},
+		{JSON, true},
+		{Badge, true},
+		{" Badge ", true},
+		{"yaml", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.supported, IsSupportedFormat(tt.format), "format %q", tt.format)
+	}
+}
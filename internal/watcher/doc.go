@@ -0,0 +1,2 @@
+// Package watcher watches the file system for changes to the source code and templates used by errctl
+package watcher
@@ -0,0 +1,206 @@
+package watcher
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/juju/errors"
+	"github.com/tfadeyi/errors/internal/logging"
+)
+
+type (
+	// Watcher notifies its caller when the files under the watched paths change.
+	// Events are debounced, so a burst of writes (i.e: an editor saving several files) results in a single notification.
+	Watcher struct {
+		logger   *logging.Logger
+		debounce time.Duration
+		filter   func(path string) bool
+		notifier *fsnotify.Watcher
+		// dirs are the watched directories, their removal is notified regardless of the filter
+		dirs map[string]struct{}
+	}
+
+	// Options contains the configuration options available to the Watcher
+	Options struct {
+		Logger *logging.Logger
+		// Debounce is the quiet period the watcher waits for, after the last event, before notifying the caller.
+		Debounce time.Duration
+		// Filter reports whether a changed file should trigger a notification, leave nil to accept all files.
+		Filter func(path string) bool
+	}
+)
+
+const (
+	defaultDebounce = 300 * time.Millisecond
+)
+
+// New creates a new instance of the watcher
+func New(opts *Options) (*Watcher, error) {
+	// create default options, these will be overridden
+	if opts == nil {
+		opts = new(Options)
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultDebounce
+	}
+
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Annotate(err, "could not create file system watcher")
+	}
+
+	return &Watcher{
+		logger:   opts.Logger,
+		debounce: opts.Debounce,
+		filter:   opts.Filter,
+		notifier: notifier,
+		dirs:     map[string]struct{}{},
+	}, nil
+}
+
+// Add starts watching the given paths. Directories are watched recursively, hidden directories are skipped.
+func (w *Watcher) Add(paths ...string) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if err := w.notifier.Add(path); err != nil {
+				return errors.Annotatef(err, "could not watch file %q", path)
+			}
+			continue
+		}
+		if _, err := w.addDir(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDir watches the directory recursively, returning the files found in it accepted by the filter
+func (w *Watcher) addDir(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if w.filter == nil || w.filter(path) {
+				files = append(files, path)
+			}
+			return nil
+		}
+		if path != root && isHidden(d.Name()) {
+			return filepath.SkipDir
+		}
+		if err := w.notifier.Add(path); err != nil {
+			return errors.Annotatef(err, "could not watch directory %q", path)
+		}
+		w.dirs[path] = struct{}{}
+		return nil
+	})
+	return files, err
+}
+
+// removeDir forgets the removed directory and its sub-directories, it returns false if the path wasn't a watched
+// directory
+func (w *Watcher) removeDir(root string) bool {
+	if _, ok := w.dirs[root]; !ok {
+		return false
+	}
+	for dir := range w.dirs {
+		if dir == root || strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			delete(w.dirs, dir)
+		}
+	}
+	return true
+}
+
+func isHidden(name string) bool {
+	return len(name) > 1 && name[0] == '.'
+}
+
+// Run blocks until the context is cancelled, calling onChange with the sorted list of files that changed
+// since the previous notification. The files of the created directories are notified, so are the removed directories.
+func (w *Watcher) Run(ctx context.Context, onChange func(files []string)) error {
+	defer w.notifier.Close()
+
+	changed := map[string]struct{}{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case err, ok := <-w.notifier.Errors:
+			if !ok {
+				return nil
+			}
+			w.warn(err)
+		case event, ok := <-w.notifier.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				// newly created directories have to be watched too, the files moved in with them have changed
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if isHidden(filepath.Base(event.Name)) {
+						continue
+					}
+					files, err := w.addDir(event.Name)
+					if err != nil {
+						w.warn(err)
+					}
+					for _, file := range files {
+						changed[file] = struct{}{}
+					}
+					if len(files) > 0 {
+						timer.Reset(w.debounce)
+					}
+					continue
+				}
+			}
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				// the removed directories are notified, so the files under them are dropped
+				if w.removeDir(event.Name) {
+					changed[event.Name] = struct{}{}
+					timer.Reset(w.debounce)
+					continue
+				}
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if w.filter != nil && !w.filter(event.Name) {
+				continue
+			}
+			changed[event.Name] = struct{}{}
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			files := make([]string, 0, len(changed))
+			for file := range changed {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+			changed = map[string]struct{}{}
+			if len(files) > 0 {
+				onChange(files)
+			}
+		}
+	}
+}
+
+func (w *Watcher) warn(err error, keyValues ...interface{}) {
+	if w.logger != nil {
+		w.logger.Warn(err, keyValues...)
+	}
+}
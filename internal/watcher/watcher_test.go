@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	debounce = 100 * time.Millisecond
+	timeout  = 5 * time.Second
+)
+
+// start watches the directory until the end of the test, returning the notifications of the watcher
+func start(t *testing.T, dir string, filter func(path string) bool) <-chan []string {
+	t.Helper()
+	w, err := New(&Options{Debounce: debounce, Filter: filter})
+	require.NoError(t, err)
+	require.NoError(t, w.Add(dir))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	notifications := make(chan []string, 10)
+	go func() {
+		defer close(done)
+		_ = w.Run(ctx, func(files []string) {
+			notifications <- files
+		})
+	}()
+	return notifications
+}
+
+func write(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0644))
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	goFiles := func(path string) bool {
+		return filepath.Ext(path) == ".go" && !strings.Contains(filepath.ToSlash(path), "/excluded/")
+	}
+
+	t.Run("Successfully debounce a burst of changes into a single notification", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		notifications := start(t, dir, nil)
+
+		write(t, filepath.Join(dir, "a.go"))
+		write(t, filepath.Join(dir, "b.go"))
+		write(t, filepath.Join(dir, "a.go"))
+
+		select {
+		case files := <-notifications:
+			assert.Equal(t, []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go")}, files)
+		case <-time.After(timeout):
+			t.Fatal("the changes weren't notified")
+		}
+		select {
+		case files := <-notifications:
+			t.Fatalf("the burst of changes was notified twice: %v", files)
+		case <-time.After(3 * debounce):
+		}
+	})
+	t.Run("Successfully ignore the filtered files and the hidden directories", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "excluded"), 0755))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+		notifications := start(t, dir, goFiles)
+
+		write(t, filepath.Join(dir, "notes.txt"))
+		write(t, filepath.Join(dir, "excluded", "excluded.go"))
+		write(t, filepath.Join(dir, ".git", "hidden.go"))
+		write(t, filepath.Join(dir, "main.go"))
+
+		select {
+		case files := <-notifications:
+			assert.Equal(t, []string{filepath.Join(dir, "main.go")}, files)
+		case <-time.After(timeout):
+			t.Fatal("the changes weren't notified")
+		}
+	})
+	t.Run("Successfully notify the files of the directories moved into the watched tree", func(t *testing.T) {
+		t.Parallel()
+		dir, outside := t.TempDir(), t.TempDir()
+		notifications := start(t, dir, goFiles)
+
+		// the files exist before the directory is watched
+		pkg := filepath.Join(outside, "store")
+		require.NoError(t, os.MkdirAll(filepath.Join(pkg, "sql"), 0755))
+		write(t, filepath.Join(pkg, "store.go"))
+		write(t, filepath.Join(pkg, "README.md"))
+		write(t, filepath.Join(pkg, "sql", "sql.go"))
+		require.NoError(t, os.Rename(pkg, filepath.Join(dir, "store")))
+
+		select {
+		case files := <-notifications:
+			assert.Equal(t, []string{filepath.Join(dir, "store", "sql", "sql.go"), filepath.Join(dir, "store", "store.go")}, files)
+		case <-time.After(timeout):
+			t.Fatal("the files of the moved directory weren't notified")
+		}
+
+		// the moved directory is watched
+		write(t, filepath.Join(dir, "store", "sql", "sql.go"))
+		select {
+		case files := <-notifications:
+			assert.Equal(t, []string{filepath.Join(dir, "store", "sql", "sql.go")}, files)
+		case <-time.After(timeout):
+			t.Fatal("the changes in the moved directory weren't notified")
+		}
+	})
+	t.Run("Successfully notify the removed directories", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "store", "sql"), 0755))
+		write(t, filepath.Join(dir, "store", "store.go"))
+		notifications := start(t, dir, goFiles)
+
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "store")))
+
+		select {
+		case files := <-notifications:
+			assert.Contains(t, files, filepath.Join(dir, "store"))
+			assert.Contains(t, files, filepath.Join(dir, "store", "store.go"))
+		case <-time.After(timeout):
+			t.Fatal("the removed directory wasn't notified")
+		}
+	})
+}
@@ -1,4 +1,5 @@
-// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+// The types of the application error manifest mirror schema/schema.json, they're maintained by hand: the schema and
+// the types are updated together.
 
 package api
 
@@ -6,7 +7,13 @@ import "encoding/json"
 import "fmt"
 
 type ErrorMetaLoc struct {
-	// Full path of the error location
+	// Column of the error annotation in the source file, starting at 1.
+	Column *int `json:"column,omitempty" yaml:"column,omitempty" mapstructure:"column,omitempty"`
+
+	// Line of the error annotation in the source file, starting at 1.
+	Line *int `json:"line,omitempty" yaml:"line,omitempty" mapstructure:"line,omitempty"`
+
+	// Path of the source file defining the error, relative to the root of its module.
 	Path string `json:"path" yaml:"path" mapstructure:"path"`
 }
 
@@ -30,10 +37,27 @@ func (j *ErrorMetaLoc) UnmarshalJSON(b []byte) error {
 
 // Metadata information about the error.
 type ErrorMeta struct {
+	// Name of the function or method enclosing the error annotation, i.e: (*Parser).Parse.
+	Function *string `json:"function,omitempty" yaml:"function,omitempty" mapstructure:"function,omitempty"`
+
 	// Loc corresponds to the JSON schema field "loc".
 	Loc *ErrorMetaLoc `json:"loc,omitempty" yaml:"loc,omitempty" mapstructure:"loc,omitempty"`
+
+	// Import path of the package defining the error.
+	Package *string `json:"package,omitempty" yaml:"package,omitempty" mapstructure:"package,omitempty"`
+
+	// Location of the fragment files supplying fields of the error, keyed by field, i.e: long or solutions.retry.short.
+	// The other fields are supplied by the annotation at loc.
+	Sources ErrorMetaSources `json:"sources,omitempty" yaml:"sources,omitempty" mapstructure:"sources,omitempty"`
+
+	// Qualified name of the Go constant declaring the error code, i.e: store.ErrNotFound.
+	Symbol *string `json:"symbol,omitempty" yaml:"symbol,omitempty" mapstructure:"symbol,omitempty"`
 }
 
+// Location of the fragment files supplying fields of the error, keyed by field, i.e: long or solutions.retry.short.
+// The other fields are supplied by the annotation at loc.
+type ErrorMetaSources map[string]ErrorMetaLoc
+
 type Solution struct {
 	// Unique identifier of the error solution.
 	Code string `json:"code" yaml:"code" mapstructure:"code"`
@@ -79,6 +103,9 @@ type Error struct {
 	// Metadata information about the error.
 	Meta *ErrorMeta `json:"meta,omitempty" yaml:"meta,omitempty" mapstructure:"meta,omitempty"`
 
+	// Free-form key/value information about the error, i.e: owner, component or ticket.
+	Metadata ErrorMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty" mapstructure:"metadata,omitempty"`
+
 	// Short short of the error. (max: 70 characters)
 	Short string `json:"short" yaml:"short" mapstructure:"short"`
 
@@ -89,6 +116,9 @@ type Error struct {
 	Title string `json:"title" yaml:"title" mapstructure:"title"`
 }
 
+// Free-form key/value information about the error, i.e: owner, component or ticket.
+type ErrorMetadata map[string]string
+
 type Solutions map[string]Solution
 
 // UnmarshalJSON implements json.Unmarshaler.